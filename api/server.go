@@ -0,0 +1,166 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/audit"
+	"github.com/coinbase-samples/prime-liquidator-go/config"
+	"github.com/coinbase-samples/prime-liquidator-go/monitor"
+	"github.com/coinbase-samples/prime-liquidator-go/report"
+	"go.uber.org/zap"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type healthResponse struct {
+	Status       string                `json:"status"`
+	Paused       bool                  `json:"paused"`
+	OrderBreaker monitor.BreakerStatus `json:"order_breaker"`
+}
+
+// StartServer serves the liquidator health, status, and metrics on the
+// configured status port. If the port is not set, nil is returned.
+//
+//	GET /health     - 200 while the liquidator is running, 503 once halted
+//	GET /status     - the liquidator status and latest asset outcomes
+//	GET /debug/vars - metrics published with expvar
+//
+//	GET /report/proceeds?from=YYYY-MM-DD&to=YYYY-MM-DD
+//	                - realised proceeds from the audit log
+//	GET /report/tca?from=YYYY-MM-DD&to=YYYY-MM-DD
+//	                - execution quality from the audit log
+func StartServer(config *config.AppConfig, l *monitor.Liquidator) *http.Server {
+
+	if len(config.StatusPort) == 0 {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+
+		s := l.Status()
+
+		res := &healthResponse{Status: "ok", Paused: s.Paused, OrderBreaker: s.OrderBreaker}
+		code := http.StatusOK
+
+		if !s.Running {
+			res.Status = "halted"
+			code = http.StatusServiceUnavailable
+		} else if s.OrderBreaker.State != monitor.BreakerClosed {
+			res.Status = "degraded"
+		}
+
+		writeJson(w, code, res)
+	})
+
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		writeJson(w, http.StatusOK, l.Status())
+	})
+
+	mux.HandleFunc("/report/proceeds", func(w http.ResponseWriter, r *http.Request) {
+
+		records, code, err := readAuditLog(config, r)
+		if err != nil {
+			writeJson(w, code, &errorResponse{Error: err.Error()})
+			return
+		}
+
+		writeJson(w, http.StatusOK, report.NewProceeds(records))
+	})
+
+	mux.HandleFunc("/report/tca", func(w http.ResponseWriter, r *http.Request) {
+
+		records, code, err := readAuditLog(config, r)
+		if err != nil {
+			writeJson(w, code, &errorResponse{Error: err.Error()})
+			return
+		}
+
+		writeJson(w, http.StatusOK, report.NewTca(records))
+	})
+
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%s", config.StatusPort),
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			zap.L().Error("status server stopped", zap.Error(err))
+		}
+	}()
+
+	return srv
+}
+
+func StopServer(srv *http.Server) error {
+
+	if srv == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	return srv.Shutdown(ctx)
+}
+
+// readAuditLog returns the audit records in the date range of the
+// request and the status code if they cannot be read.
+func readAuditLog(config *config.AppConfig, r *http.Request) ([]*audit.Record, int, error) {
+
+	if len(config.AuditLogPath) == 0 {
+		return nil, http.StatusNotFound, errors.New("audit log is not configured")
+	}
+
+	start, end, err := report.ParseRange(r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	records, err := audit.ReadFile(config.AuditLogPath, start, end)
+	if err != nil {
+		zap.L().Error("cannot read audit log", zap.Error(err))
+		return nil, http.StatusInternalServerError, errors.New("cannot read audit log")
+	}
+
+	return records, http.StatusOK, nil
+}
+
+func writeJson(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		zap.L().Error("cannot write response", zap.Error(err))
+	}
+}
@@ -0,0 +1,78 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// maxRecordSize is the longest audit log line that can be read.
+const maxRecordSize = 1024 * 1024
+
+// ReadFile returns the records in the audit log at the path with a time
+// from the start up to, but not including, the end. A zero bound is not
+// applied.
+func ReadFile(path string, start, end time.Time) ([]*Record, error) {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open audit log: %s - err: %w", path, err)
+	}
+	defer f.Close()
+
+	return Read(f, start, end)
+}
+
+// Read returns the JSONL records in the reader within the time range.
+func Read(r io.Reader, start, end time.Time) (records []*Record, err error) {
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRecordSize)
+
+	var line int
+	for scanner.Scan() {
+
+		line++
+
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		rec := &Record{}
+		if err = json.Unmarshal(scanner.Bytes(), rec); err != nil {
+			err = fmt.Errorf("cannot parse audit record - line: %d - err: %w", line, err)
+			return
+		}
+
+		if (!start.IsZero() && rec.Time.Before(start)) || (!end.IsZero() && !rec.Time.Before(end)) {
+			continue
+		}
+
+		records = append(records, rec)
+	}
+
+	if err = scanner.Err(); err != nil {
+		err = fmt.Errorf("cannot read audit log: %w", err)
+	}
+
+	return
+}
@@ -0,0 +1,70 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRead(t *testing.T) {
+
+	log := strings.Join([]string{
+		`{"kind":"order","time":"2024-03-01T10:00:00Z","symbol":"BTC"}`,
+		`{"kind":"order","time":"2024-03-02T10:00:00Z","symbol":"ETH"}`,
+		``,
+		`{"kind":"order","time":"2024-03-03T00:00:00Z","symbol":"SOL"}`,
+	}, "\n")
+
+	start := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		description string
+		start       time.Time
+		end         time.Time
+		expected    string
+	}{
+		{description: "TestReadAll", expected: "BTC,ETH,SOL"},
+		{description: "TestReadStart", start: start, expected: "ETH,SOL"},
+		{description: "TestReadRange", start: start, end: end, expected: "ETH"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			records, err := Read(strings.NewReader(log), tt.start, tt.end)
+			if err != nil {
+				t.Fatalf("test: %s - cannot read: %v", tt.description, err)
+			}
+
+			var symbols []string
+			for _, r := range records {
+				symbols = append(symbols, r.Symbol)
+			}
+
+			if result := strings.Join(symbols, ","); result != tt.expected {
+				t.Errorf("test: %s - expected: %s - received: %s", tt.description, tt.expected, result)
+			}
+		})
+	}
+
+	if _, err := Read(strings.NewReader("{"), time.Time{}, time.Time{}); err == nil {
+		t.Errorf("expected an error for an invalid record")
+	}
+}
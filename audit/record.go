@@ -0,0 +1,172 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"time"
+
+	prime "github.com/coinbase-samples/prime-sdk-go"
+)
+
+type Kind string
+
+const (
+	KindDecision   Kind = "decision"
+	KindConversion Kind = "conversion"
+	KindTransfer   Kind = "transfer"
+	KindOrder      Kind = "order"
+	KindFill       Kind = "fill"
+)
+
+// Record is a single entry in the audit log. A decision record captures
+// the inputs and the outcome of processing an asset on one loop iteration.
+// A conversion or transfer record captures the final status of a
+// conversion or wallet transfer. An order record captures the fills of an
+// order once it is filled or expires, with the Exchange price at decision
+// time as the price and, for TWAP orders, the time weighted Exchange price
+// while the order was working. A fill record captures one of its fills.
+type Record struct {
+	Kind Kind      `json:"kind"`
+	Time time.Time `json:"time"`
+
+	// Inputs
+	Symbol         string `json:"symbol"`
+	Amount         string `json:"amount,omitempty"`
+	Holds          string `json:"holds,omitempty"`
+	Price          string `json:"price,omitempty"`
+	ProductId      string `json:"product_id,omitempty"`
+	BaseIncrement  string `json:"base_increment,omitempty"`
+	QuoteIncrement string `json:"quote_increment,omitempty"`
+	BaseMinSize    string `json:"base_min_size,omitempty"`
+	BaseMaxSize    string `json:"base_max_size,omitempty"`
+	QuoteMinSize   string `json:"quote_min_size,omitempty"`
+	Sweep          bool   `json:"sweep,omitempty"`
+
+	// Decision
+	Action        string `json:"action"`
+	SkipReason    string `json:"skip_reason,omitempty"`
+	OrderType     string `json:"order_type,omitempty"`
+	OrderSize     string `json:"order_size,omitempty"`
+	Value         string `json:"value,omitempty"`
+	LimitPrice    string `json:"limit_price,omitempty"`
+	Duration      string `json:"duration,omitempty"`
+	ClientOrderId string `json:"client_order_id,omitempty"`
+	OrderId       string `json:"order_id,omitempty"`
+	ActivityId    string `json:"activity_id,omitempty"`
+	Error         string `json:"error,omitempty"`
+
+	// Execution
+	Side           string     `json:"side,omitempty"`
+	FillId         string     `json:"fill_id,omitempty"`
+	Venue          string     `json:"venue,omitempty"`
+	FilledQuantity string     `json:"filled_quantity,omitempty"`
+	FilledValue    string     `json:"filled_value,omitempty"`
+	AveragePrice   string     `json:"average_price,omitempty"`
+	MarketTwap     string     `json:"market_twap,omitempty"`
+	SubmittedAt    *time.Time `json:"submitted_at,omitempty"`
+
+	// Transfer, or the destination symbol of a conversion
+	WalletId    string `json:"wallet_id,omitempty"`
+	Destination string `json:"destination,omitempty"`
+
+	// Settlement
+	Status        string `json:"status,omitempty"`
+	SettledAmount string `json:"settled_amount,omitempty"`
+	Fees          string `json:"fees,omitempty"`
+}
+
+// NewDecision returns a decision record for the balance.
+func NewDecision(asset *prime.Balance) *Record {
+	return &Record{
+		Kind:   KindDecision,
+		Time:   time.Now().UTC(),
+		Symbol: asset.Symbol,
+		Amount: asset.Amount,
+		Holds:  asset.Holds,
+	}
+}
+
+// NewConversion returns a conversion record for the activity.
+func NewConversion(activityId, symbol, amount string) *Record {
+	return &Record{
+		Kind:       KindConversion,
+		Time:       time.Now().UTC(),
+		Symbol:     symbol,
+		Amount:     amount,
+		Action:     "conversion",
+		ActivityId: activityId,
+	}
+}
+
+// NewTransfer returns a transfer record for the activity.
+func NewTransfer(activityId, symbol, amount, walletId, destination string) *Record {
+	return &Record{
+		Kind:        KindTransfer,
+		Time:        time.Now().UTC(),
+		Symbol:      symbol,
+		Amount:      amount,
+		Action:      "transfer",
+		ActivityId:  activityId,
+		WalletId:    walletId,
+		Destination: destination,
+	}
+}
+
+// NewOrder returns an order record for the order.
+func NewOrder(orderId, clientOrderId, symbol, productId, orderType string) *Record {
+	return &Record{
+		Kind:          KindOrder,
+		Time:          time.Now().UTC(),
+		Symbol:        symbol,
+		ProductId:     productId,
+		Action:        "order",
+		OrderType:     orderType,
+		OrderId:       orderId,
+		ClientOrderId: clientOrderId,
+	}
+}
+
+// NewFill returns a fill record for the order fill.
+func NewFill(orderId, clientOrderId, symbol, orderType string, fill *prime.OrderFill) *Record {
+	return &Record{
+		Kind:           KindFill,
+		Time:           fill.Time.UTC(),
+		Symbol:         symbol,
+		ProductId:      fill.ProductId,
+		Action:         "fill",
+		OrderType:      orderType,
+		OrderId:        orderId,
+		ClientOrderId:  clientOrderId,
+		Side:           fill.Side,
+		FillId:         fill.Id,
+		Venue:          fill.Venue,
+		Price:          fill.Price,
+		FilledQuantity: fill.FilledQuantity,
+		FilledValue:    fill.FilledValue,
+		Fees:           fill.Commission,
+	}
+}
+
+// SetProduct records the product increments and limits.
+func (r *Record) SetProduct(p *prime.Product) {
+	r.ProductId = p.Id
+	r.BaseIncrement = p.BaseIncrement
+	r.QuoteIncrement = p.QuoteIncrement
+	r.BaseMinSize = p.BaseMinSize
+	r.BaseMaxSize = p.BaseMaxSize
+	r.QuoteMinSize = p.QuoteMinSize
+}
@@ -0,0 +1,89 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/coinbase-samples/prime-liquidator-go/config"
+)
+
+// Store persists audit records. Records are never updated or removed.
+type Store interface {
+	Append(r *Record) error
+	Close() error
+}
+
+// NewStore returns a JSONL store at the configured audit log path. If
+// the path is not set, records are discarded.
+func NewStore(config *config.AppConfig) (Store, error) {
+	if len(config.AuditLogPath) == 0 {
+		return nopStore{}, nil
+	}
+	return OpenFileStore(config.AuditLogPath)
+}
+
+type nopStore struct{}
+
+func (s nopStore) Append(r *Record) error { return nil }
+
+func (s nopStore) Close() error { return nil }
+
+// FileStore appends records as JSON lines to a file.
+type FileStore struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenFileStore opens the file for appending, creating it if needed.
+func OpenFileStore(path string) (*FileStore, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open audit log: %s - err: %w", path, err)
+	}
+	return &FileStore{file: f}, nil
+}
+
+func (s *FileStore) Append(r *Record) error {
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("cannot marshal audit record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("cannot write audit record: %w", err)
+	}
+
+	return nil
+}
+
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.file.Sync(); err != nil {
+		return err
+	}
+	return s.file.Close()
+}
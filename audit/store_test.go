@@ -0,0 +1,76 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	prime "github.com/coinbase-samples/prime-sdk-go"
+)
+
+func TestFileStoreAppend(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	// Open twice to verify that existing records are preserved
+	for _, symbol := range []string{"BTC", "ETH"} {
+
+		s, err := OpenFileStore(path)
+		if err != nil {
+			t.Fatalf("cannot open store: %v", err)
+		}
+
+		r := NewDecision(&prime.Balance{Symbol: symbol, Amount: "1", Holds: "0"})
+		r.Action = "skip"
+		r.SkipReason = "zero_order_size"
+
+		if err := s.Append(r); err != nil {
+			t.Fatalf("cannot append record: %v", err)
+		}
+
+		if err := s.Close(); err != nil {
+			t.Fatalf("cannot close store: %v", err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("cannot open audit log: %v", err)
+	}
+	defer f.Close()
+
+	var symbols []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("cannot unmarshal record: %v", err)
+		}
+		if r.Kind != KindDecision || r.Action != "skip" || r.SkipReason != "zero_order_size" {
+			t.Errorf("unexpected record: %+v", r)
+		}
+		symbols = append(symbols, r.Symbol)
+	}
+
+	if len(symbols) != 2 || symbols[0] != "BTC" || symbols[1] != "ETH" {
+		t.Errorf("expected: [BTC ETH] - received: %v", symbols)
+	}
+}
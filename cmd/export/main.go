@@ -0,0 +1,82 @@
+/**
+ * Copyright 2023-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/coinbase-samples/prime-liquidator-go/audit"
+	"github.com/coinbase-samples/prime-liquidator-go/config"
+	"github.com/coinbase-samples/prime-liquidator-go/report"
+)
+
+// The export command writes the orders, fills, and conversions in the
+// audit log as CSV or JSON, e.g.,
+//
+//	export -from 2024-01-01 -to 2024-12-31 -format csv -out liquidations.csv
+func main() {
+
+	appConfig := &config.AppConfig{}
+
+	if err := config.SetupAppConfig(appConfig); err != nil {
+		fatal("cannot setup app config: %v", err)
+	}
+
+	path := flag.String("audit-log", appConfig.AuditLogPath, "audit log path")
+	from := flag.String("from", "", "first UTC date, YYYY-MM-DD")
+	to := flag.String("to", "", "last UTC date, YYYY-MM-DD")
+	format := flag.String("format", report.FormatCsv, "csv or json")
+	out := flag.String("out", "", "output file, defaults to stdout")
+	flag.Parse()
+
+	if len(*path) == 0 {
+		fatal("audit log path is not set")
+	}
+
+	start, end, err := report.ParseRange(*from, *to)
+	if err != nil {
+		fatal("%v", err)
+	}
+
+	records, err := audit.ReadFile(*path, start, end)
+	if err != nil {
+		fatal("%v", err)
+	}
+
+	var w io.Writer = os.Stdout
+
+	if len(*out) > 0 {
+		f, err := os.Create(*out)
+		if err != nil {
+			fatal("cannot create output file: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := report.WriteExport(w, report.NewExport(records), *format); err != nil {
+		fatal("cannot write export: %v", err)
+	}
+}
+
+func fatal(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,80 @@
+/**
+ * Copyright 2023-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/coinbase-samples/prime-liquidator-go/audit"
+	"github.com/coinbase-samples/prime-liquidator-go/config"
+	"github.com/coinbase-samples/prime-liquidator-go/report"
+)
+
+// The report command writes the realised proceeds or the execution quality
+// of the liquidator orders in the audit log, e.g.,
+//
+//	report -from 2024-03-01 -to 2024-03-31 -format json
+//	report -report tca -from 2024-03-01
+func main() {
+
+	appConfig := &config.AppConfig{}
+
+	if err := config.SetupAppConfig(appConfig); err != nil {
+		fatal("cannot setup app config: %v", err)
+	}
+
+	path := flag.String("audit-log", appConfig.AuditLogPath, "audit log path")
+	from := flag.String("from", "", "first UTC date, YYYY-MM-DD")
+	to := flag.String("to", "", "last UTC date, YYYY-MM-DD")
+	name := flag.String("report", "proceeds", "proceeds or tca")
+	format := flag.String("format", report.FormatText, "text or json")
+	flag.Parse()
+
+	if len(*path) == 0 {
+		fatal("audit log path is not set")
+	}
+
+	start, end, err := report.ParseRange(*from, *to)
+	if err != nil {
+		fatal("%v", err)
+	}
+
+	records, err := audit.ReadFile(*path, start, end)
+	if err != nil {
+		fatal("%v", err)
+	}
+
+	switch *name {
+	case "proceeds":
+		err = report.WriteProceeds(os.Stdout, report.NewProceeds(records), *format)
+	case "tca":
+		err = report.WriteTca(os.Stdout, report.NewTca(records), *format)
+	default:
+		err = fmt.Errorf("unknown report: %s", *name)
+	}
+
+	if err != nil {
+		fatal("cannot write report: %v", err)
+	}
+}
+
+func fatal(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
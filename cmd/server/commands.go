@@ -0,0 +1,338 @@
+/**
+ * Copyright 2023-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/config"
+	"github.com/coinbase-samples/prime-liquidator-go/monitor"
+	prime "github.com/coinbase-samples/prime-sdk-go"
+	"github.com/shopspring/decimal"
+)
+
+const statusTimeout = 10 * time.Second
+
+type command struct {
+	description string
+	usage       string
+	minArgs     int
+	maxArgs     int
+	prime       bool // requires the prime credentials
+	loadsConfig bool // loads the config itself rather than exiting if it is invalid
+	run         func(appConfig *config.AppConfig, args []string) error
+}
+
+var commands = map[string]command{
+	"run": {
+		description: "run the liquidator until interrupted (default)",
+		prime:       true,
+		run:         runDaemon,
+	},
+	"once": {
+		description: "make a single pass over the trading balances",
+		prime:       true,
+		run:         runOnce,
+	},
+	"plan": {
+		description: "print what a pass would sell or convert",
+		prime:       true,
+		run:         runPlan,
+	},
+	"status": {
+		description: "print the status of a running liquidator",
+		usage:       "[url]",
+		maxArgs:     1,
+		run:         runStatus,
+	},
+	"cancel-all": {
+		description: "cancel the open sell orders for fiat submitted by the liquidator",
+		usage:       "[--all] [symbol]",
+		maxArgs:     2,
+		prime:       true,
+		run:         runCancelAll,
+	},
+	"liquidate": {
+		description: "sell or convert the balance of an asset",
+		usage:       "<symbol> [amount]",
+		minArgs:     1,
+		maxArgs:     2,
+		prime:       true,
+		run:         runLiquidate,
+	},
+	"validate-config": {
+		description: "check the settings and report every problem",
+		loadsConfig: true,
+		run:         runValidateConfig,
+	},
+	"convert": {
+		description: "convert an amount between trading wallets",
+		usage:       "<from> <to> <amount>",
+		minArgs:     3,
+		maxArgs:     3,
+		prime:       true,
+		run:         runConvert,
+	},
+}
+
+func runOnce(appConfig *config.AppConfig, args []string) error {
+	return withLiquidator(appConfig, func(l *monitor.Liquidator) error {
+		outcomes, err := l.RunOnce()
+		writeOutcomes(os.Stdout, outcomes...)
+		return err
+	})
+}
+
+func runPlan(appConfig *config.AppConfig, args []string) error {
+	return withLiquidator(appConfig, func(l *monitor.Liquidator) error {
+		entries, err := l.Plan()
+		writePlan(os.Stdout, entries)
+		return err
+	})
+}
+
+func runLiquidate(appConfig *config.AppConfig, args []string) error {
+
+	amount := decimal.Zero
+	if len(args) > 1 {
+		var err error
+		if amount, err = parseAmount(args[1]); err != nil {
+			return err
+		}
+	}
+
+	return withLiquidator(appConfig, func(l *monitor.Liquidator) error {
+		o, err := l.Liquidate(args[0], amount)
+		if o != nil {
+			writeOutcomes(os.Stdout, o)
+		}
+		return err
+	})
+}
+
+func runConvert(appConfig *config.AppConfig, args []string) error {
+
+	amount, err := parseAmount(args[2])
+	if err != nil {
+		return err
+	}
+
+	return withLiquidator(appConfig, func(l *monitor.Liquidator) error {
+		o, err := l.Convert(args[0], args[1], amount)
+		if o != nil {
+			writeOutcomes(os.Stdout, o)
+		}
+		return err
+	})
+}
+
+// runCancelAll cancels the open liquidator orders, optionally only those
+// for the symbol. The --all flag may come before or after the symbol and
+// also cancels orders not submitted by the liquidator.
+func runCancelAll(appConfig *config.AppConfig, args []string) error {
+
+	var all bool
+	var symbols []string
+	for _, arg := range args {
+		switch {
+		case arg == "--all" || arg == "-all":
+			all = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			symbols = append(symbols, arg)
+		}
+	}
+
+	if len(symbols) > 1 {
+		return fmt.Errorf("unexpected arguments: %v", symbols[1:])
+	}
+
+	var symbol string
+	if len(symbols) > 0 {
+		symbol = symbols[0]
+	}
+
+	return withLiquidator(appConfig, func(l *monitor.Liquidator) error {
+		orders, err := l.CancelAll(symbol, all)
+		writeOrders(os.Stdout, orders)
+		return err
+	})
+}
+
+// runStatus prints the status of a liquidator serving on the configured
+// status port, or at the url.
+func runStatus(appConfig *config.AppConfig, args []string) error {
+
+	url := fmt.Sprintf("http://localhost:%s/status", appConfig.StatusPort)
+	if len(args) > 0 {
+		url = args[0]
+	} else if len(appConfig.StatusPort) == 0 {
+		return fmt.Errorf("status port is not set")
+	}
+
+	client := &http.Client{Timeout: statusTimeout}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return fmt.Errorf("cannot get status: %s - err: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("cannot read status: %s - err: %w", url, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d - body: %s", resp.StatusCode, body)
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, body, "", "  "); err != nil {
+		return fmt.Errorf("cannot format status: %w", err)
+	}
+
+	out.WriteByte('\n')
+
+	_, err = out.WriteTo(os.Stdout)
+	return err
+}
+
+// runValidateConfig prints every invalid setting, e.g., before deploying
+// a new config file.
+func runValidateConfig(appConfig *config.AppConfig, args []string) error {
+
+	var invalid *config.ValidationError
+	if err := loadConfig(appConfig); errors.As(err, &invalid) {
+		for _, problem := range invalid.Problems {
+			fmt.Println(problem)
+		}
+		return fmt.Errorf("%d invalid settings", len(invalid.Problems))
+	} else if err != nil {
+		return err
+	}
+
+	fmt.Println("config is valid")
+
+	return nil
+}
+
+// withLiquidator runs the one-shot command and closes the liquidator, so
+// the audit log and pending notifications are flushed.
+func withLiquidator(appConfig *config.AppConfig, run func(l *monitor.Liquidator) error) error {
+
+	l, err := monitor.NewLiquidator(appConfig)
+	if err != nil {
+		return err
+	}
+
+	err = run(l)
+
+	if cErr := l.Close(); cErr != nil && err == nil {
+		err = cErr
+	}
+
+	return err
+}
+
+func parseAmount(v string) (decimal.Decimal, error) {
+
+	amount, err := decimal.NewFromString(v)
+	if err != nil {
+		return amount, fmt.Errorf("invalid amount: %s - err: %w", v, err)
+	}
+
+	if !amount.IsPositive() {
+		return amount, fmt.Errorf("amount must be positive: %s", v)
+	}
+
+	return amount, nil
+}
+
+func writeOutcomes(w io.Writer, outcomes ...*monitor.Outcome) {
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "SYMBOL\tACTION\tREASON\tORDER TYPE\tORDER ID\tACTIVITY ID\tERROR")
+
+	for _, o := range outcomes {
+		fmt.Fprintf(
+			tw,
+			"%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			o.Symbol,
+			o.Action,
+			o.SkipReason,
+			o.OrderType,
+			o.OrderId,
+			o.ActivityId,
+			o.Error,
+		)
+	}
+
+	tw.Flush()
+}
+
+func writePlan(w io.Writer, entries []*monitor.PlanEntry) {
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "SYMBOL\tBALANCE\tHOLDS\tPRICE\tORDER SIZE\tNOTIONAL\tSTRATEGY\tLIMIT PRICE\tDURATION\tACTION\tREASON\tERROR")
+
+	for _, e := range entries {
+		fmt.Fprintf(
+			tw,
+			"%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.Symbol,
+			e.Balance,
+			e.Holds,
+			e.Price,
+			e.OrderSize,
+			e.Notional,
+			e.Strategy,
+			e.LimitPrice,
+			e.Duration,
+			e.Action,
+			e.SkipReason,
+			e.Error,
+		)
+	}
+
+	tw.Flush()
+}
+
+func writeOrders(w io.Writer, orders []*prime.Order) {
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "ORDER ID\tPRODUCT ID\tORDER TYPE\tBASE QUANTITY\tFILLED QUANTITY")
+
+	for _, o := range orders {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", o.Id, o.ProductId, o.Type, o.BaseQuantity, o.FilledQuantity)
+	}
+
+	tw.Flush()
+}
@@ -17,47 +17,92 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"os/signal"
+	"sort"
 	"syscall"
 
+	"github.com/coinbase-samples/prime-liquidator-go/api"
 	"github.com/coinbase-samples/prime-liquidator-go/config"
 	"github.com/coinbase-samples/prime-liquidator-go/monitor"
 	prime "github.com/coinbase-samples/prime-sdk-go"
 	"go.uber.org/zap"
 )
 
+// The server runs the liquidator daemon by default. The other commands
+// make a single pass or a single change and exit, e.g.,
+//
+//	server once
+//	server liquidate eth 1.5
+//	server convert usdc usd 25000
 func main() {
 
-	run := make(chan os.Signal, 1)
-	signal.Notify(run, os.Interrupt, syscall.SIGTERM)
+	name := "run"
+	args := os.Args[1:]
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
 
-	log := config.LogInit("prime-liquidator")
+	if len(args) < cmd.minArgs || len(args) > cmd.maxArgs {
+		fmt.Fprintf(os.Stderr, "usage: server %s %s\n", name, cmd.usage)
+		os.Exit(2)
+	}
+
+	log := config.CommandLogInit("prime-liquidator")
+	if name == "run" {
+		log = config.LogInit("prime-liquidator")
+	}
 	zap.ReplaceGlobals(log)
 	defer log.Sync()
 
-	log.Info("prime-liquidator", zap.String("state", "starting"))
+	if name == "run" {
+		log.Info("prime-liquidator", zap.String("state", "starting"))
+	}
 
 	if err := os.Setenv("TZ", "UTC"); err != nil {
 		log.Fatal("cannot set time zone: UTC", zap.Error(err))
 	}
 
-	if err := os.Setenv("TZ", "UTC"); err != nil {
-		log.Fatal("Cannot set time zone: UTC", zap.Error(err))
-	}
+	appConfig := &config.AppConfig{}
 
-	credentials, err := prime.ReadEnvCredentials("PRIME_CREDENTIALS")
-	if err != nil {
-		log.Fatal("cannot init the prime credentials", zap.Error(err))
+	if !cmd.loadsConfig {
+		var invalid *config.ValidationError
+		if err := loadConfig(appConfig); errors.As(err, &invalid) {
+			log.Fatal("invalid config", zap.Strings("problems", invalid.Problems))
+		} else if err != nil {
+			log.Fatal("cannot setup app config", zap.Error(err))
+		}
 	}
 
-	appConfig := &config.AppConfig{}
+	if cmd.prime {
+		credentials, err := prime.ReadEnvCredentials("PRIME_CREDENTIALS")
+		if err != nil {
+			log.Fatal("cannot init the prime credentials", zap.Error(err))
+		}
+		appConfig.PrimeClient = prime.NewClient(credentials, *appConfig.HttpClient)
+	}
 
-	if err := config.SetupAppConfig(appConfig); err != nil {
-		log.Fatal("cannot setup app config", zap.Error(err))
+	if err := cmd.run(appConfig, args); err != nil {
+		log.Sync()
+		fatal("%s: %v", name, err)
 	}
+}
+
+// runDaemon continuously liquidates until the process is interrupted.
+func runDaemon(appConfig *config.AppConfig, args []string) error {
+
+	run := make(chan os.Signal, 1)
+	signal.Notify(run, os.Interrupt, syscall.SIGTERM)
 
-	appConfig.PrimeClient = prime.NewClient(credentials, *appConfig.HttpClient)
+	log := zap.L()
 
 	log.Info("watch for crypto assets in hot/trading wallets and sell")
 
@@ -66,16 +111,65 @@ func main() {
 		log.Fatal("cannot start liquidator", zap.Error(err))
 	}
 
+	srv := api.StartServer(appConfig, daemon)
+
 	log.Info("prime-liquidator", zap.String("state", "started"))
 
 	<-run
 
 	log.Info("prime-liquidator", zap.String("state", "stopping"))
 
+	if err := api.StopServer(srv); err != nil {
+		log.Error("status server did not stop cleanly", zap.Error(err))
+	}
+
 	if err := monitor.StopLiquidator(daemon); err != nil {
 		log.Error("process did not stop cleanly", zap.Error(err))
 	}
 
 	log.Info("prime-liquidator", zap.String("state", "stopped"))
 
+	return nil
+}
+
+// loadConfig reads and validates the settings, including the settings
+// parsed by the liquidator, so every problem is reported at once.
+func loadConfig(appConfig *config.AppConfig) error {
+
+	var problems []string
+
+	var invalid *config.ValidationError
+	if err := config.SetupAppConfig(appConfig); errors.As(err, &invalid) {
+		problems = invalid.Problems
+	} else if err != nil {
+		return err
+	}
+
+	problems = append(problems, monitor.ValidateConfig(appConfig)...)
+
+	if len(problems) > 0 {
+		return &config.ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+func usage() {
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(os.Stderr, "usage: server <command> [arguments]")
+	fmt.Fprintln(os.Stderr)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", name, commands[name].description)
+	}
+}
+
+func fatal(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
 }
@@ -19,6 +19,8 @@ package config
 import (
 	"fmt"
 	"net/http"
+	"os"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -42,25 +44,84 @@ type AppConfig struct {
 	HttpTLSHandshakeInSeconds   string `mapstructure:"HTTP_TLS_HANDSHAKE"`
 	EnvName                     string `mapstructure:"ENV_NAME"`
 	FiatCurrencySymbol          string `mapstructure:"FIAT_CURRENCY_SYMBOL"`
-	TwapDurationInMinutes       string `mapstructure:"TWAP_DURATION"` // This should be at least 60'
+	TwapDurationInMinutes       string `mapstructure:"TWAP_DURATION"` // at least 60
 	PrimeCallTimeoutInSeconds   string `mapstructure:"PRIME_CALL_TIMEOUT"`
 	OrdersCacheSizeInItems      string `mapstructure:"ORDERS_CACHE_SIZE"`
 	ConvertSymbolsArray         string `mapstructure:"CONVERT_SYMBOLS"`
+	ConversionRoutesArray       string `mapstructure:"CONVERSION_ROUTES"` // e.g., usd->usdc:min=1000:digits=2,pyusd->usd
 	TwapMinNotionalPerHour      string `mapstructure:"TWAP_MIN_NOTIONAL"`
+	TradingWindowsArray         string `mapstructure:"TRADING_WINDOWS"`       // e.g., mon-fri 13:00-21:00,sat 15:00-17:00
+	AssetTradingWindowsArray    string `mapstructure:"ASSET_TRADING_WINDOWS"` // e.g., eth=mon-fri 14:00-20:00;sol=daily 00:00-24:00
+	BlackoutDatesArray          string `mapstructure:"BLACKOUT_DATES"`        // e.g., 2024-12-25,2024-12-31/2025-01-01
+	TriggerMinValueAmount       string `mapstructure:"TRIGGER_MIN_VALUE"`
+	AssetTriggerMinValuesArray  string `mapstructure:"ASSET_TRIGGER_MIN_VALUES"` // e.g., btc=500,eth=250
+	DustSweepIntervalInMinutes  string `mapstructure:"DUST_SWEEP_INTERVAL"`      // 0 disables the dust sweep
+	WebhookUrlsArray            string `mapstructure:"WEBHOOK_URLS"`
+	SlackWebhookUrlsArray       string `mapstructure:"SLACK_WEBHOOK_URLS"`
+	WebhookSigningSecret        string `mapstructure:"WEBHOOK_SIGNING_SECRET"`
+	WebhookMaxRetriesCount      string `mapstructure:"WEBHOOK_MAX_RETRIES"`
+	WebhookTimeoutInSeconds     string `mapstructure:"WEBHOOK_TIMEOUT"`
+	AuditLogPath                string `mapstructure:"AUDIT_LOG_PATH"`
+	RetryMaxAttemptsCount       string `mapstructure:"RETRY_MAX_ATTEMPTS"`
+	RetryInitialBackoffInMillis string `mapstructure:"RETRY_INITIAL_BACKOFF"`
+	RetryMaxBackoffInMillis     string `mapstructure:"RETRY_MAX_BACKOFF"`
+	RetryJitterInPercent        string `mapstructure:"RETRY_JITTER"`
+	RetryStatusCodesArray       string `mapstructure:"RETRY_STATUS_CODES"`
+	PrimeRateLimitPerSecond     string `mapstructure:"PRIME_RATE_LIMIT"`
+	PrimeRateBurstCount         string `mapstructure:"PRIME_RATE_BURST"`
+	ExchangeRateLimitPerSecond  string `mapstructure:"EXCHANGE_RATE_LIMIT"`
+	ExchangeRateBurstCount      string `mapstructure:"EXCHANGE_RATE_BURST"`
+	OrderBreakerThresholdCount  string `mapstructure:"ORDER_BREAKER_THRESHOLD"` // 0 disables the breaker
+	OrderBreakerCoolDownInMins  string `mapstructure:"ORDER_BREAKER_COOL_DOWN"`
+	StatusPort                  string `mapstructure:"STATUS_PORT"` // empty disables the status server
+	AssetConcurrencyCount       string `mapstructure:"ASSET_CONCURRENCY"`
+	PrimeFeedUrl                string `mapstructure:"PRIME_FEED_URL"` // empty disables the feed
+	PollIntervalInSeconds       string `mapstructure:"POLL_INTERVAL"`
+	PollMinIntervalInSeconds    string `mapstructure:"POLL_MIN_INTERVAL"`
+	PollMaxIntervalInSeconds    string `mapstructure:"POLL_MAX_INTERVAL"`
+	ErrorIntervalInSeconds      string `mapstructure:"ERROR_INTERVAL"`
+	LeaseBackend                string `mapstructure:"LEASE_BACKEND"` // file or table, empty disables leader election
+	LeaseName                   string `mapstructure:"LEASE_NAME"`
+	LeaseTtlInSeconds           string `mapstructure:"LEASE_TTL"`
+	LeaseFilePath               string `mapstructure:"LEASE_FILE_PATH"`
+	LeaseDatabaseDriver         string `mapstructure:"LEASE_DATABASE_DRIVER"`
+	LeaseDatabaseUrl            string `mapstructure:"LEASE_DATABASE_URL"`
+	LeaseTableName              string `mapstructure:"LEASE_TABLE"`
+	ConversionStuckInMinutes    string `mapstructure:"CONVERSION_STUCK_AFTER"`
+	WalletsRefreshInMinutes     string `mapstructure:"WALLETS_REFRESH_INTERVAL"`  // 0 refreshes every pass
+	ProductsRefreshInMinutes    string `mapstructure:"PRODUCTS_REFRESH_INTERVAL"` // 0 refreshes every pass
+	VaultSweepSymbolsArray      string `mapstructure:"VAULT_SWEEP_SYMBOLS"`       // empty disables the vault sweep
+	VaultSweepIntervalInMinutes string `mapstructure:"VAULT_SWEEP_INTERVAL"`
+	ProceedsSweepThresholdValue string `mapstructure:"PROCEEDS_SWEEP_THRESHOLD"` // empty disables the proceeds sweep
+	ProceedsSweepSymbol         string `mapstructure:"PROCEEDS_SWEEP_SYMBOL"`    // empty sweeps the fiat currency
+	ProceedsSweepWalletId       string `mapstructure:"PROCEEDS_SWEEP_WALLET_ID"`
+	ProceedsSweepPaymentMethod  string `mapstructure:"PROCEEDS_SWEEP_PAYMENT_METHOD_ID"`
+	ProceedsSweepAddress        string `mapstructure:"PROCEEDS_SWEEP_ADDRESS"`
+	ProceedsSweepMaxValue       string `mapstructure:"PROCEEDS_SWEEP_MAX_AMOUNT"` // 0 disables the cap
+	ProceedsSweepDailyCapValue  string `mapstructure:"PROCEEDS_SWEEP_DAILY_CAP"`  // 0 disables the cap
+	ProceedsSweepIntervalInMins string `mapstructure:"PROCEEDS_SWEEP_INTERVAL"`
 
 	TwapMaxDiscountPercent decimal.Decimal
-	StablecoinFiatDigits   int32
 }
 
 func (a AppConfig) IsLocalEnv() bool {
 	return a.EnvName == "local"
 }
 
+// SetupAppConfig reads the settings from the environment, falling back
+// to the YAML or TOML file at CONFIG_FILE, or else a .env file, and then
+// the defaults. The settings are validated before they are used.
 func SetupAppConfig(app *AppConfig) error {
 
-	viper.AddConfigPath(".")
-	viper.SetConfigName(".env")
-	viper.SetConfigType("env")
+	configFile := os.Getenv("CONFIG_FILE")
+
+	if len(configFile) > 0 {
+		viper.SetConfigFile(configFile)
+	} else {
+		viper.AddConfigPath(".")
+		viper.SetConfigName(".env")
+		viper.SetConfigType("env")
+	}
 
 	viper.AutomaticEnv()
 	viper.AllowEmptyEnv(true)
@@ -78,15 +139,75 @@ func SetupAppConfig(app *AppConfig) error {
 	viper.SetDefault("FIAT_CURRENCY_SYMBOL", "USD")
 	viper.SetDefault("ORDERS_CACHE_SIZE", "1000")
 	viper.SetDefault("CONVERT_SYMBOLS", "usdc")
+	viper.SetDefault("CONVERSION_ROUTES", "")
 	viper.SetDefault("TWAP_DURATION", "60")
 	viper.SetDefault("TWAP_MIN_NOTIONAL", "100")
+	viper.SetDefault("TRADING_WINDOWS", "")
+	viper.SetDefault("ASSET_TRADING_WINDOWS", "")
+	viper.SetDefault("BLACKOUT_DATES", "")
+	viper.SetDefault("TRIGGER_MIN_VALUE", "0")
+	viper.SetDefault("ASSET_TRIGGER_MIN_VALUES", "")
+	viper.SetDefault("DUST_SWEEP_INTERVAL", "0")
+	viper.SetDefault("WEBHOOK_URLS", "")
+	viper.SetDefault("SLACK_WEBHOOK_URLS", "")
+	viper.SetDefault("WEBHOOK_SIGNING_SECRET", "")
+	viper.SetDefault("WEBHOOK_MAX_RETRIES", "3")
+	viper.SetDefault("WEBHOOK_TIMEOUT", "5")
+	viper.SetDefault("AUDIT_LOG_PATH", "")
+	viper.SetDefault("RETRY_MAX_ATTEMPTS", "3")
+	viper.SetDefault("RETRY_INITIAL_BACKOFF", "250")
+	viper.SetDefault("RETRY_MAX_BACKOFF", "5000")
+	viper.SetDefault("RETRY_JITTER", "20")
+	viper.SetDefault("RETRY_STATUS_CODES", "408,429,500,502,503,504")
+	viper.SetDefault("PRIME_RATE_LIMIT", "25")
+	viper.SetDefault("PRIME_RATE_BURST", "50")
+	viper.SetDefault("EXCHANGE_RATE_LIMIT", "10")
+	viper.SetDefault("EXCHANGE_RATE_BURST", "15")
+	viper.SetDefault("ORDER_BREAKER_THRESHOLD", "5")
+	viper.SetDefault("ORDER_BREAKER_COOL_DOWN", "15")
+	viper.SetDefault("STATUS_PORT", "")
+	viper.SetDefault("ASSET_CONCURRENCY", "4")
+	viper.SetDefault("PRIME_FEED_URL", "")
+	viper.SetDefault("POLL_INTERVAL", "5")
+	viper.SetDefault("POLL_MIN_INTERVAL", "2")
+	viper.SetDefault("POLL_MAX_INTERVAL", "30")
+	viper.SetDefault("ERROR_INTERVAL", "5")
+	viper.SetDefault("LEASE_BACKEND", "")
+	viper.SetDefault("LEASE_NAME", "prime-liquidator")
+	viper.SetDefault("LEASE_TTL", "30")
+	viper.SetDefault("LEASE_FILE_PATH", "/tmp/prime-liquidator.lock")
+	viper.SetDefault("LEASE_DATABASE_DRIVER", "pgx")
+	viper.SetDefault("LEASE_DATABASE_URL", "")
+	viper.SetDefault("LEASE_TABLE", "liquidator_leases")
+	viper.SetDefault("CONVERSION_STUCK_AFTER", "15")
+	viper.SetDefault("WALLETS_REFRESH_INTERVAL", "15")
+	viper.SetDefault("PRODUCTS_REFRESH_INTERVAL", "60")
+	viper.SetDefault("VAULT_SWEEP_SYMBOLS", "")
+	viper.SetDefault("VAULT_SWEEP_INTERVAL", "5")
+	viper.SetDefault("PROCEEDS_SWEEP_THRESHOLD", "")
+	viper.SetDefault("PROCEEDS_SWEEP_SYMBOL", "")
+	viper.SetDefault("PROCEEDS_SWEEP_WALLET_ID", "")
+	viper.SetDefault("PROCEEDS_SWEEP_PAYMENT_METHOD_ID", "")
+	viper.SetDefault("PROCEEDS_SWEEP_ADDRESS", "")
+	viper.SetDefault("PROCEEDS_SWEEP_MAX_AMOUNT", "0")
+	viper.SetDefault("PROCEEDS_SWEEP_DAILY_CAP", "0")
+	viper.SetDefault("PROCEEDS_SWEEP_INTERVAL", "60")
+
+	if err := viper.ReadInConfig(); err != nil && len(configFile) > 0 {
+		return fmt.Errorf("cannot read config file: %s - err: %w", configFile, err)
+	}
 
-	viper.ReadInConfig()
-
-	if err := viper.Unmarshal(&app); err != nil {
+	if err := viper.Unmarshal(&app, viper.DecodeHook(joinListHook)); err != nil {
+		if len(configFile) > 0 {
+			return fmt.Errorf("cannot parse config file: %s - err: %w", configFile, err)
+		}
 		zap.L().Debug("cannot parse env file", zap.Error(err))
 	}
 
+	if err := app.Validate(); err != nil {
+		return err
+	}
+
 	httpClient, err := InitHttpClient(app)
 	if err != nil {
 		return fmt.Errorf("cannot init the http client %w", err)
@@ -95,7 +216,6 @@ func SetupAppConfig(app *AppConfig) error {
 	app.HttpClient = httpClient
 
 	app.TwapMaxDiscountPercent = decimal.NewFromFloat32(0.1)
-	app.StablecoinFiatDigits = 2
 
 	return nil
 
@@ -105,6 +225,14 @@ func (a AppConfig) ConvertSymbols() []string {
 	return strings.Split(a.ConvertSymbolsArray, ",")
 }
 
+func (a AppConfig) WebhookUrls() []string {
+	return splitArray(a.WebhookUrlsArray)
+}
+
+func (a AppConfig) SlackWebhookUrls() []string {
+	return splitArray(a.SlackWebhookUrlsArray)
+}
+
 func (a AppConfig) TwapDuration() time.Duration {
 	return convertStrIntToDurationOrFatal(a.TwapDurationInMinutes, "TwapDurationInMinutes", time.Minute)
 }
@@ -141,6 +269,134 @@ func (a AppConfig) TwapMinNotional() int {
 	return convertStrIntOrFatal(a.TwapMinNotionalPerHour, "TwapMinNotionalPerHour")
 }
 
+func (a AppConfig) TriggerMinValue() decimal.Decimal {
+	return convertStrDecimalOrFatal(a.TriggerMinValueAmount, "TriggerMinValueAmount")
+}
+
+func (a AppConfig) DustSweepInterval() time.Duration {
+	return convertStrIntToDurationOrFatal(a.DustSweepIntervalInMinutes, "DustSweepIntervalInMinutes", time.Minute)
+}
+
+func (a AppConfig) WebhookMaxRetries() int {
+	return convertStrIntOrFatal(a.WebhookMaxRetriesCount, "WebhookMaxRetriesCount")
+}
+
+func (a AppConfig) WebhookTimeout() time.Duration {
+	return convertStrIntToDurationOrFatal(a.WebhookTimeoutInSeconds, "WebhookTimeoutInSeconds", time.Second)
+}
+
+func (a AppConfig) RetryMaxAttempts() int {
+	return convertStrIntOrFatal(a.RetryMaxAttemptsCount, "RetryMaxAttemptsCount")
+}
+
+func (a AppConfig) RetryInitialBackoff() time.Duration {
+	return convertStrIntToDurationOrFatal(a.RetryInitialBackoffInMillis, "RetryInitialBackoffInMillis", time.Millisecond)
+}
+
+func (a AppConfig) RetryMaxBackoff() time.Duration {
+	return convertStrIntToDurationOrFatal(a.RetryMaxBackoffInMillis, "RetryMaxBackoffInMillis", time.Millisecond)
+}
+
+func (a AppConfig) RetryJitterPercent() int {
+	return convertStrIntOrFatal(a.RetryJitterInPercent, "RetryJitterInPercent")
+}
+
+func (a AppConfig) RetryStatusCodes() (codes []int) {
+	for _, v := range splitArray(a.RetryStatusCodesArray) {
+		codes = append(codes, convertStrIntOrFatal(v, "RetryStatusCodesArray"))
+	}
+	return
+}
+
+func (a AppConfig) PrimeRateLimit() int {
+	return convertStrIntOrFatal(a.PrimeRateLimitPerSecond, "PrimeRateLimitPerSecond")
+}
+
+func (a AppConfig) PrimeRateBurst() int {
+	return convertStrIntOrFatal(a.PrimeRateBurstCount, "PrimeRateBurstCount")
+}
+
+func (a AppConfig) ExchangeRateLimit() int {
+	return convertStrIntOrFatal(a.ExchangeRateLimitPerSecond, "ExchangeRateLimitPerSecond")
+}
+
+func (a AppConfig) ExchangeRateBurst() int {
+	return convertStrIntOrFatal(a.ExchangeRateBurstCount, "ExchangeRateBurstCount")
+}
+
+func (a AppConfig) OrderBreakerThreshold() int {
+	return convertStrIntOrFatal(a.OrderBreakerThresholdCount, "OrderBreakerThresholdCount")
+}
+
+func (a AppConfig) OrderBreakerCoolDown() time.Duration {
+	return convertStrIntToDurationOrFatal(a.OrderBreakerCoolDownInMins, "OrderBreakerCoolDownInMins", time.Minute)
+}
+
+// AssetConcurrency returns the number of assets processed in parallel.
+// Values below one are treated as one.
+func (a AppConfig) AssetConcurrency() int {
+	if n := convertStrIntOrFatal(a.AssetConcurrencyCount, "AssetConcurrencyCount"); n > 1 {
+		return n
+	}
+	return 1
+}
+
+func (a AppConfig) PollInterval() time.Duration {
+	return convertStrIntToDurationOrFatal(a.PollIntervalInSeconds, "PollIntervalInSeconds", time.Second)
+}
+
+func (a AppConfig) PollMinInterval() time.Duration {
+	return convertStrIntToDurationOrFatal(a.PollMinIntervalInSeconds, "PollMinIntervalInSeconds", time.Second)
+}
+
+func (a AppConfig) PollMaxInterval() time.Duration {
+	return convertStrIntToDurationOrFatal(a.PollMaxIntervalInSeconds, "PollMaxIntervalInSeconds", time.Second)
+}
+
+func (a AppConfig) ErrorInterval() time.Duration {
+	return convertStrIntToDurationOrFatal(a.ErrorIntervalInSeconds, "ErrorIntervalInSeconds", time.Second)
+}
+
+func (a AppConfig) LeaseTtl() time.Duration {
+	return convertStrIntToDurationOrFatal(a.LeaseTtlInSeconds, "LeaseTtlInSeconds", time.Second)
+}
+
+func (a AppConfig) ConversionStuckAfter() time.Duration {
+	return convertStrIntToDurationOrFatal(a.ConversionStuckInMinutes, "ConversionStuckInMinutes", time.Minute)
+}
+
+func (a AppConfig) WalletsRefreshInterval() time.Duration {
+	return convertStrIntToDurationOrFatal(a.WalletsRefreshInMinutes, "WalletsRefreshInMinutes", time.Minute)
+}
+
+func (a AppConfig) ProductsRefreshInterval() time.Duration {
+	return convertStrIntToDurationOrFatal(a.ProductsRefreshInMinutes, "ProductsRefreshInMinutes", time.Minute)
+}
+
+func (a AppConfig) VaultSweepSymbols() []string {
+	return splitArray(a.VaultSweepSymbolsArray)
+}
+
+func (a AppConfig) VaultSweepInterval() time.Duration {
+	return convertStrIntToDurationOrFatal(a.VaultSweepIntervalInMinutes, "VaultSweepIntervalInMinutes", time.Minute)
+}
+
+func (a AppConfig) ProceedsSweepThreshold() decimal.Decimal {
+	return convertStrDecimalOrFatal(a.ProceedsSweepThresholdValue, "ProceedsSweepThresholdValue")
+}
+
+func (a AppConfig) ProceedsSweepMaxAmount() decimal.Decimal {
+	return convertStrDecimalOrFatal(a.ProceedsSweepMaxValue, "ProceedsSweepMaxValue")
+}
+
+func (a AppConfig) ProceedsSweepDailyCap() decimal.Decimal {
+	return convertStrDecimalOrFatal(a.ProceedsSweepDailyCapValue, "ProceedsSweepDailyCapValue")
+}
+
+func (a AppConfig) ProceedsSweepInterval() time.Duration {
+	return convertStrIntToDurationOrFatal(a.ProceedsSweepIntervalInMins, "ProceedsSweepIntervalInMins", time.Minute)
+}
+
 func (a AppConfig) HttpTLSHandshake() time.Duration {
 	return convertStrIntToDurationOrFatal(a.HttpTLSHandshakeInSeconds, "HttpTLSHandshakeInSeconds", time.Second)
 }
@@ -153,6 +409,33 @@ func (a AppConfig) HttpMaxHostIdleConns() int {
 	return convertStrIntOrFatal(a.HttpMaxHostIdleConnsCount, "HttpMaxHostIdleConnsCount")
 }
 
+// joinListHook joins lists in a config file into the comma separated
+// values of the array settings, e.g., convert_symbols: [usdc, pyusd].
+func joinListHook(from, to reflect.Type, data interface{}) (interface{}, error) {
+
+	if to.Kind() != reflect.String || (from.Kind() != reflect.Slice && from.Kind() != reflect.Array) {
+		return data, nil
+	}
+
+	list := reflect.ValueOf(data)
+
+	values := make([]string, list.Len())
+	for i := range values {
+		values[i] = fmt.Sprint(list.Index(i).Interface())
+	}
+
+	return strings.Join(values, ","), nil
+}
+
+func splitArray(v string) (values []string) {
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); len(s) > 0 {
+			values = append(values, s)
+		}
+	}
+	return
+}
+
 func convertStrIntToDurationOrFatal(v, n string, dt time.Duration) time.Duration {
 	i, err := convertStrIntToDuration(v, dt)
 	if err != nil {
@@ -169,6 +452,14 @@ func convertStrIntOrFatal(v, n string) int {
 	return i
 }
 
+func convertStrDecimalOrFatal(v, n string) decimal.Decimal {
+	d, err := decimal.NewFromString(v)
+	if err != nil {
+		zap.L().Fatal("cannot convert string to decimal", zap.String("value", v), zap.String("name", n), zap.Error(err))
+	}
+	return d
+}
+
 func convertStrIntToDuration(s string, dt time.Duration) (time.Duration, error) {
 	i, err := strconv.Atoi(s)
 	if err != nil {
@@ -24,6 +24,16 @@ import (
 )
 
 func LogInit(taskName string) *zap.Logger {
+	return logInit(os.Stdout)
+}
+
+// CommandLogInit logs to stderr, so the output of one-shot commands on
+// stdout is not mixed with log lines.
+func CommandLogInit(taskName string) *zap.Logger {
+	return logInit(os.Stderr)
+}
+
+func logInit(w zapcore.WriteSyncer) *zap.Logger {
 
 	log := zap.Must(zap.NewProduction())
 
@@ -35,7 +45,7 @@ func LogInit(taskName string) *zap.Logger {
 	encoder := zapcore.NewJSONEncoder(config)
 
 	core := zapcore.NewTee(
-		zapcore.NewCore(encoder, zapcore.AddSync(os.Stdout), zap.InfoLevel),
+		zapcore.NewCore(encoder, zapcore.AddSync(w), zap.InfoLevel),
 	)
 
 	return zap.New(core, zap.AddCaller())
@@ -0,0 +1,252 @@
+/**
+ * Copyright 2023-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// minTwapDurationInMinutes is the shortest TWAP duration that Prime
+// accepts for the liquidator's orders.
+const minTwapDurationInMinutes = 60
+
+var symbolPattern = regexp.MustCompile(`^[A-Za-z0-9]{1,20}$`)
+
+// ValidationError lists every invalid setting, so they can be fixed at
+// once rather than one restart at a time.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid config - %s", strings.Join(e.Problems, "; "))
+}
+
+// Validate checks the required settings and that numeric settings parse
+// and are in range. It returns a *ValidationError with every problem, or
+// nil. The accessors cannot fail on a config that is valid.
+func (a AppConfig) Validate() error {
+
+	v := &validator{}
+
+	v.required("FIAT_CURRENCY_SYMBOL", a.FiatCurrencySymbol)
+	if len(a.FiatCurrencySymbol) > 0 {
+		v.symbol("FIAT_CURRENCY_SYMBOL", a.FiatCurrencySymbol)
+	}
+
+	v.intAtLeast("PRIME_CALL_TIMEOUT", a.PrimeCallTimeoutInSeconds, 1)
+	v.intAtLeast("HTTP_CONNECT_TIMEOUT", a.HttpConnectTimeoutInSeconds, 1)
+	v.intAtLeast("HTTP_CONN_KEEP_ALIVE", a.HttpConnKeepAliveInSeconds, 0)
+	v.intAtLeast("HTTP_EXPECT_CONTINUE", a.HttpExpectContinueInSeconds, 0)
+	v.intAtLeast("HTTP_IDLE_CONN", a.HttpIdleConnInSeconds, 0)
+	v.intAtLeast("HTTP_MAX_ALL_IDLE_CONNS", a.HttpMaxAllIdleConnsCount, 0)
+	v.intAtLeast("HTTP_MAX_HOST_IDLE_CONNS", a.HttpMaxHostIdleConnsCount, 0)
+	v.intAtLeast("HTTP_RESPONSE_HEADER", a.HttpResponseHeaderInSeconds, 0)
+	v.intAtLeast("HTTP_TLS_HANDSHAKE", a.HttpTLSHandshakeInSeconds, 0)
+
+	v.intAtLeast("TWAP_DURATION", a.TwapDurationInMinutes, minTwapDurationInMinutes)
+	v.intAtLeast("TWAP_MIN_NOTIONAL", a.TwapMinNotionalPerHour, 0)
+	v.intAtLeast("ORDERS_CACHE_SIZE", a.OrdersCacheSizeInItems, 1)
+	v.decimalAtLeast("TRIGGER_MIN_VALUE", a.TriggerMinValueAmount, decimal.Zero)
+	v.intAtLeast("DUST_SWEEP_INTERVAL", a.DustSweepIntervalInMinutes, 0)
+
+	for _, u := range splitArray(a.WebhookUrlsArray) {
+		v.url("WEBHOOK_URLS", u, "http", "https")
+	}
+	for _, u := range splitArray(a.SlackWebhookUrlsArray) {
+		v.url("SLACK_WEBHOOK_URLS", u, "http", "https")
+	}
+	v.intAtLeast("WEBHOOK_MAX_RETRIES", a.WebhookMaxRetriesCount, 0)
+	v.intAtLeast("WEBHOOK_TIMEOUT", a.WebhookTimeoutInSeconds, 1)
+
+	v.intAtLeast("RETRY_MAX_ATTEMPTS", a.RetryMaxAttemptsCount, 1)
+	initialBackoff := v.intAtLeast("RETRY_INITIAL_BACKOFF", a.RetryInitialBackoffInMillis, 0)
+	maxBackoff := v.intAtLeast("RETRY_MAX_BACKOFF", a.RetryMaxBackoffInMillis, 0)
+	if maxBackoff < initialBackoff {
+		v.add("RETRY_MAX_BACKOFF", "must be at least RETRY_INITIAL_BACKOFF", a.RetryMaxBackoffInMillis)
+	}
+	v.intBetween("RETRY_JITTER", a.RetryJitterInPercent, 0, 100)
+	for _, code := range splitArray(a.RetryStatusCodesArray) {
+		v.intBetween("RETRY_STATUS_CODES", code, 100, 599)
+	}
+
+	v.intAtLeast("PRIME_RATE_LIMIT", a.PrimeRateLimitPerSecond, 1)
+	v.intAtLeast("PRIME_RATE_BURST", a.PrimeRateBurstCount, 1)
+	v.intAtLeast("EXCHANGE_RATE_LIMIT", a.ExchangeRateLimitPerSecond, 1)
+	v.intAtLeast("EXCHANGE_RATE_BURST", a.ExchangeRateBurstCount, 1)
+
+	v.intAtLeast("ORDER_BREAKER_THRESHOLD", a.OrderBreakerThresholdCount, 0)
+	v.intAtLeast("ORDER_BREAKER_COOL_DOWN", a.OrderBreakerCoolDownInMins, 0)
+
+	if len(a.StatusPort) > 0 {
+		v.intBetween("STATUS_PORT", a.StatusPort, 1, 65535)
+	}
+
+	v.int("ASSET_CONCURRENCY", a.AssetConcurrencyCount)
+
+	poll := v.intAtLeast("POLL_INTERVAL", a.PollIntervalInSeconds, 1)
+	pollMin := v.intAtLeast("POLL_MIN_INTERVAL", a.PollMinIntervalInSeconds, 0)
+	pollMax := v.intAtLeast("POLL_MAX_INTERVAL", a.PollMaxIntervalInSeconds, 1)
+	if pollMax < pollMin {
+		v.add("POLL_MAX_INTERVAL", "must be at least POLL_MIN_INTERVAL", a.PollMaxIntervalInSeconds)
+	} else if poll >= 1 && poll < pollMin {
+		v.add("POLL_INTERVAL", "must be at least POLL_MIN_INTERVAL", a.PollIntervalInSeconds)
+	} else if poll >= 1 && poll > pollMax {
+		v.add("POLL_INTERVAL", "must be at most POLL_MAX_INTERVAL", a.PollIntervalInSeconds)
+	}
+	v.intAtLeast("ERROR_INTERVAL", a.ErrorIntervalInSeconds, 0)
+
+	switch a.LeaseBackend {
+	case "":
+	case "file":
+		v.required("LEASE_FILE_PATH", a.LeaseFilePath)
+	case "table":
+		v.required("LEASE_NAME", a.LeaseName)
+		v.required("LEASE_DATABASE_DRIVER", a.LeaseDatabaseDriver)
+		v.required("LEASE_DATABASE_URL", a.LeaseDatabaseUrl)
+		v.required("LEASE_TABLE", a.LeaseTableName)
+	default:
+		v.add("LEASE_BACKEND", "must be file, table, or empty", a.LeaseBackend)
+	}
+
+	// The lease is renewed every third of the TTL
+	v.intAtLeast("LEASE_TTL", a.LeaseTtlInSeconds, 3)
+
+	v.intAtLeast("CONVERSION_STUCK_AFTER", a.ConversionStuckInMinutes, 1)
+	v.intAtLeast("WALLETS_REFRESH_INTERVAL", a.WalletsRefreshInMinutes, 0)
+	v.intAtLeast("PRODUCTS_REFRESH_INTERVAL", a.ProductsRefreshInMinutes, 0)
+	if len(a.PrimeFeedUrl) > 0 {
+		v.url("PRIME_FEED_URL", a.PrimeFeedUrl, "ws", "wss")
+	}
+
+	for _, symbol := range splitArray(a.VaultSweepSymbolsArray) {
+		v.symbol("VAULT_SWEEP_SYMBOLS", symbol)
+	}
+	v.intAtLeast("VAULT_SWEEP_INTERVAL", a.VaultSweepIntervalInMinutes, 1)
+
+	if len(a.ProceedsSweepThresholdValue) > 0 {
+		v.decimalAtLeast("PROCEEDS_SWEEP_THRESHOLD", a.ProceedsSweepThresholdValue, decimal.Zero)
+
+		destinations := 0
+		for _, d := range []string{a.ProceedsSweepWalletId, a.ProceedsSweepPaymentMethod, a.ProceedsSweepAddress} {
+			if len(d) > 0 {
+				destinations++
+			}
+		}
+
+		if destinations != 1 {
+			v.problems = append(v.problems, "PROCEEDS_SWEEP_WALLET_ID, PROCEEDS_SWEEP_PAYMENT_METHOD_ID, or PROCEEDS_SWEEP_ADDRESS: exactly one must be set")
+		}
+	}
+
+	if len(a.ProceedsSweepSymbol) > 0 {
+		v.symbol("PROCEEDS_SWEEP_SYMBOL", a.ProceedsSweepSymbol)
+	}
+	v.decimalAtLeast("PROCEEDS_SWEEP_MAX_AMOUNT", a.ProceedsSweepMaxValue, decimal.Zero)
+	v.decimalAtLeast("PROCEEDS_SWEEP_DAILY_CAP", a.ProceedsSweepDailyCapValue, decimal.Zero)
+	v.intAtLeast("PROCEEDS_SWEEP_INTERVAL", a.ProceedsSweepIntervalInMins, 1)
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+
+	return nil
+}
+
+// validator collects a problem for each invalid setting.
+type validator struct {
+	problems []string
+}
+
+func (v *validator) add(name, problem, value string) {
+	v.problems = append(v.problems, fmt.Sprintf("%s: %s - value: %q", name, problem, value))
+}
+
+func (v *validator) required(name, value string) {
+	if len(strings.TrimSpace(value)) == 0 {
+		v.problems = append(v.problems, fmt.Sprintf("%s: is required", name))
+	}
+}
+
+// int returns the parsed value, or zero if it is not an integer.
+func (v *validator) int(name, value string) int {
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		v.add(name, "must be an integer", value)
+	}
+	return i
+}
+
+func (v *validator) intAtLeast(name, value string, min int) int {
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		v.add(name, "must be an integer", value)
+	} else if i < min {
+		v.add(name, fmt.Sprintf("must be at least %d", min), value)
+	}
+	return i
+}
+
+func (v *validator) intBetween(name, value string, min, max int) {
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		v.add(name, "must be an integer", value)
+	} else if i < min || i > max {
+		v.add(name, fmt.Sprintf("must be between %d and %d", min, max), value)
+	}
+}
+
+// symbol checks that the value looks like an asset symbol, e.g., eth or
+// USDC. Whether Prime supports the asset is not checked.
+func (v *validator) symbol(name, value string) {
+	if !symbolPattern.MatchString(value) {
+		v.add(name, "must be an asset symbol of letters and digits", value)
+	}
+}
+
+// url checks that the value is an absolute URL with one of the schemes.
+func (v *validator) url(name, value string, schemes ...string) {
+
+	u, err := url.Parse(value)
+	if err != nil || len(u.Host) == 0 {
+		v.add(name, "must be an absolute URL", value)
+		return
+	}
+
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return
+		}
+	}
+
+	v.add(name, fmt.Sprintf("must use the %s scheme", strings.Join(schemes, " or ")), value)
+}
+
+func (v *validator) decimalAtLeast(name, value string, min decimal.Decimal) {
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		v.add(name, "must be a number", value)
+	} else if d.LessThan(min) {
+		v.add(name, fmt.Sprintf("must be at least %s", min), value)
+	}
+}
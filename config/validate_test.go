@@ -0,0 +1,281 @@
+/**
+ * Copyright 2023-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+
+	valid := func() AppConfig {
+		return AppConfig{
+			FiatCurrencySymbol:          "USD",
+			PrimeCallTimeoutInSeconds:   "10",
+			HttpConnectTimeoutInSeconds: "5",
+			HttpConnKeepAliveInSeconds:  "30",
+			HttpExpectContinueInSeconds: "1",
+			HttpIdleConnInSeconds:       "90",
+			HttpMaxAllIdleConnsCount:    "10",
+			HttpMaxHostIdleConnsCount:   "5",
+			HttpResponseHeaderInSeconds: "5",
+			HttpTLSHandshakeInSeconds:   "5",
+			TwapDurationInMinutes:       "60",
+			TwapMinNotionalPerHour:      "100",
+			OrdersCacheSizeInItems:      "1000",
+			TriggerMinValueAmount:       "0",
+			DustSweepIntervalInMinutes:  "0",
+			WebhookMaxRetriesCount:      "3",
+			WebhookTimeoutInSeconds:     "5",
+			RetryMaxAttemptsCount:       "3",
+			RetryInitialBackoffInMillis: "250",
+			RetryMaxBackoffInMillis:     "5000",
+			RetryJitterInPercent:        "20",
+			RetryStatusCodesArray:       "429,503",
+			PrimeRateLimitPerSecond:     "25",
+			PrimeRateBurstCount:         "50",
+			ExchangeRateLimitPerSecond:  "10",
+			ExchangeRateBurstCount:      "15",
+			OrderBreakerThresholdCount:  "5",
+			OrderBreakerCoolDownInMins:  "15",
+			AssetConcurrencyCount:       "4",
+			PollIntervalInSeconds:       "5",
+			PollMinIntervalInSeconds:    "2",
+			PollMaxIntervalInSeconds:    "30",
+			ErrorIntervalInSeconds:      "5",
+			LeaseTtlInSeconds:           "30",
+			ConversionStuckInMinutes:    "15",
+			WalletsRefreshInMinutes:     "15",
+			ProductsRefreshInMinutes:    "60",
+			VaultSweepIntervalInMinutes: "5",
+			ProceedsSweepMaxValue:       "0",
+			ProceedsSweepDailyCapValue:  "0",
+			ProceedsSweepIntervalInMins: "60",
+		}
+	}
+
+	cases := []struct {
+		description string
+		update      func(a *AppConfig)
+		expected    []string
+	}{
+		{
+			description: "TestValidateDefaults",
+			update:      func(a *AppConfig) {},
+		},
+		{
+			description: "TestValidateReportsEveryProblem",
+			update: func(a *AppConfig) {
+				a.FiatCurrencySymbol = ""
+				a.TwapDurationInMinutes = "30"
+				a.OrdersCacheSizeInItems = "0"
+				a.RetryJitterInPercent = "abc"
+				a.TriggerMinValueAmount = "-1"
+			},
+			expected: []string{"FIAT_CURRENCY_SYMBOL", "TWAP_DURATION", "ORDERS_CACHE_SIZE", "TRIGGER_MIN_VALUE", "RETRY_JITTER"},
+		},
+		{
+			description: "TestValidateCrossFieldRanges",
+			update: func(a *AppConfig) {
+				a.RetryMaxBackoffInMillis = "100"
+				a.RetryStatusCodesArray = "429,42"
+				a.StatusPort = "70000"
+			},
+			expected: []string{"RETRY_MAX_BACKOFF", "RETRY_STATUS_CODES", "STATUS_PORT"},
+		},
+		{
+			description: "TestValidatePollIntervalBelowMin",
+			update: func(a *AppConfig) {
+				a.PollIntervalInSeconds = "1"
+			},
+			expected: []string{"POLL_INTERVAL"},
+		},
+		{
+			description: "TestValidatePollIntervalAboveMax",
+			update: func(a *AppConfig) {
+				a.PollIntervalInSeconds = "60"
+			},
+			expected: []string{"POLL_INTERVAL"},
+		},
+		{
+			description: "TestValidateLeaseBackend",
+			update: func(a *AppConfig) {
+				a.LeaseBackend = "table"
+			},
+			expected: []string{"LEASE_NAME", "LEASE_DATABASE_DRIVER", "LEASE_DATABASE_URL", "LEASE_TABLE"},
+		},
+		{
+			description: "TestValidateProceedsSweepDestination",
+			update: func(a *AppConfig) {
+				a.ProceedsSweepThresholdValue = "1000"
+				a.ProceedsSweepWalletId = "wallet"
+				a.ProceedsSweepAddress = "0xabc"
+			},
+			expected: []string{"PROCEEDS_SWEEP_WALLET_ID"},
+		},
+		{
+			description: "TestValidateUrlsAndSymbols",
+			update: func(a *AppConfig) {
+				a.WebhookUrlsArray = "https://hooks.example.com/liquidator,http://localhost:8080"
+				a.SlackWebhookUrlsArray = "https://hooks.slack.com/services/T/B/X"
+				a.PrimeFeedUrl = "wss://ws-feed.prime.coinbase.com"
+				a.VaultSweepSymbolsArray = "eth,SOL,1inch"
+				a.ProceedsSweepSymbol = "usdc"
+			},
+		},
+		{
+			description: "TestValidateInvalidUrlsAndSymbols",
+			update: func(a *AppConfig) {
+				a.WebhookUrlsArray = "https://hooks.example.com,hooks.example.com/liquidator"
+				a.SlackWebhookUrlsArray = "ftp://hooks.slack.com"
+				a.PollMinIntervalInSeconds = "60"
+				a.PrimeFeedUrl = "https://ws-feed.prime.coinbase.com"
+				a.VaultSweepSymbolsArray = "eth,so-l"
+				a.ProceedsSweepSymbol = "us d"
+			},
+			expected: []string{
+				"WEBHOOK_URLS",
+				"SLACK_WEBHOOK_URLS",
+				"POLL_MAX_INTERVAL",
+				"PRIME_FEED_URL",
+				"VAULT_SWEEP_SYMBOLS",
+				"PROCEEDS_SWEEP_SYMBOL",
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			a := valid()
+			tt.update(&a)
+
+			err := a.Validate()
+
+			if len(tt.expected) == 0 {
+				if err != nil {
+					t.Fatalf("test: %s - unexpected error: %v", tt.description, err)
+				}
+				return
+			}
+
+			var invalid *ValidationError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("test: %s - expected a validation error - received: %v", tt.description, err)
+			}
+
+			if len(invalid.Problems) != len(tt.expected) {
+				t.Fatalf("test: %s - expected: %v - received: %v", tt.description, tt.expected, invalid.Problems)
+			}
+
+			for i, name := range tt.expected {
+				if !strings.HasPrefix(invalid.Problems[i], name) {
+					t.Errorf("test: %s - expected: %s - received: %s", tt.description, name, invalid.Problems[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSetupAppConfigFile(t *testing.T) {
+
+	cases := []struct {
+		description string
+		name        string
+		contents    string
+	}{
+		{
+			description: "TestSetupAppConfigYaml",
+			name:        "liquidator.yaml",
+			contents:    "twap_duration: 120\nconvert_symbols: [usdc, pyusd]\ntrigger_min_value: 2.5\n",
+		},
+		{
+			description: "TestSetupAppConfigToml",
+			name:        "liquidator.toml",
+			contents:    "twap_duration = 120\nconvert_symbols = [\"usdc\", \"pyusd\"]\ntrigger_min_value = 2.5\n",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			path := filepath.Join(t.TempDir(), tt.name)
+			if err := os.WriteFile(path, []byte(tt.contents), 0o600); err != nil {
+				t.Fatalf("cannot write config file: %v", err)
+			}
+
+			t.Setenv("CONFIG_FILE", path)
+
+			a := &AppConfig{}
+			if err := SetupAppConfig(a); err != nil {
+				t.Fatalf("test: %s - cannot setup app config: %v", tt.description, err)
+			}
+
+			if a.TwapDurationInMinutes != "120" {
+				t.Errorf("test: %s - expected: 120 - received: %s", tt.description, a.TwapDurationInMinutes)
+			}
+
+			if a.ConvertSymbolsArray != "usdc,pyusd" {
+				t.Errorf("test: %s - expected: usdc,pyusd - received: %s", tt.description, a.ConvertSymbolsArray)
+			}
+
+			if a.TriggerMinValueAmount != "2.5" {
+				t.Errorf("test: %s - expected: 2.5 - received: %s", tt.description, a.TriggerMinValueAmount)
+			}
+
+			// Defaults apply to the settings missing from the file
+			if a.OrdersCacheSizeInItems != "1000" {
+				t.Errorf("test: %s - expected: 1000 - received: %s", tt.description, a.OrdersCacheSizeInItems)
+			}
+		})
+	}
+}
+
+func TestExampleConfig(t *testing.T) {
+
+	path := filepath.Join("..", "liquidator.example.yaml")
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("cannot read example config: %v", err)
+	}
+
+	// The example lists every setting
+	fields := reflect.TypeOf(AppConfig{})
+	for i := 0; i < fields.NumField(); i++ {
+		name := fields.Field(i).Tag.Get("mapstructure")
+		if len(name) > 0 && !strings.Contains(string(contents), "\n"+strings.ToLower(name)+":") {
+			t.Errorf("expected the example config to include: %s", strings.ToLower(name))
+		}
+	}
+
+	t.Setenv("CONFIG_FILE", path)
+
+	a := &AppConfig{}
+	if err := SetupAppConfig(a); err != nil {
+		t.Fatalf("cannot setup app config from the example: %v", err)
+	}
+
+	if a.RetryStatusCodesArray != "408,429,500,502,503,504" {
+		t.Errorf("expected: 408,429,500,502,503,504 - received: %s", a.RetryStatusCodesArray)
+	}
+}
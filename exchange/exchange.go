@@ -50,6 +50,20 @@ func init() {
 	}
 }
 
+// HttpError is returned when the Exchange responds with an unexpected
+// status code.
+type HttpError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *HttpError) Error() string {
+	if len(e.Message) > 0 {
+		return fmt.Sprintf("exchange did not return 200 - val: %d - msg: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("exchange did not return 200 - val: %d", e.StatusCode)
+}
+
 type ExchangeProductPrice struct {
 	Price string `json:"price"`
 }
@@ -84,17 +98,17 @@ func CurrentProductPrice(productId string, timeout time.Duration, httpClient *ht
 		return price, fmt.Errorf("cannot read Exchange product price response - err: %w", err)
 	}
 
-	if res.StatusCode == http.StatusBadRequest && strings.Contains(string(body), "message") {
-		var errMsg prime.ErrorMessage
-		if err := json.Unmarshal(body, &errMsg); err != nil {
-			return price, fmt.Errorf("cannot unmarshal Exchange error messsage: %w", err)
-		}
+	if res.StatusCode != http.StatusOK {
+		httpErr := &HttpError{StatusCode: res.StatusCode}
 
-		return price, fmt.Errorf("cannot fetch Exchnage price price - did return 200 - val: %d - msg: %s", res.StatusCode, errMsg.Value)
-	}
+		if strings.Contains(string(body), "message") {
+			var errMsg prime.ErrorMessage
+			if err := json.Unmarshal(body, &errMsg); err == nil {
+				httpErr.Message = errMsg.Value
+			}
+		}
 
-	if res.StatusCode != http.StatusOK {
-		return price, fmt.Errorf("exchange product price did return 200 - val: %d", res.StatusCode)
+		return price, fmt.Errorf("cannot fetch Exchange product price: %s - %w", productId, httpErr)
 	}
 
 	var productPrice ExchangeProductPrice
@@ -0,0 +1,225 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package feed
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	prime "github.com/coinbase-samples/prime-sdk-go"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	feedOrigin = "https://prime.coinbase.com"
+
+	// heartbeats are published every second, so a connection without
+	// any messages for this long is considered dead.
+	readTimeout = 30 * time.Second
+
+	reconnectBackoffMin = time.Second
+	reconnectBackoffMax = time.Minute
+)
+
+// Handler is called from the feed goroutine for each order update.
+type Handler func(u *OrderUpdate)
+
+// Feed subscribes to the Prime WebSocket orders and heartbeats channels
+// for the portfolio and calls the handler for each order update. The
+// connection is re-established with an exponential backoff until the
+// feed is closed.
+type Feed struct {
+	url         string
+	credentials *prime.Credentials
+	handler     Handler
+	connected   atomic.Bool
+	closed      atomic.Bool
+	conn        *websocket.Conn
+	connLock    sync.Mutex
+	done        chan struct{}
+	wg          sync.WaitGroup
+}
+
+func NewFeed(url string, credentials *prime.Credentials, handler Handler) *Feed {
+	return &Feed{
+		url:         url,
+		credentials: credentials,
+		handler:     handler,
+		done:        make(chan struct{}),
+	}
+}
+
+// Start connects to the feed from a background goroutine.
+func (f *Feed) Start() {
+	f.wg.Add(1)
+	go f.run()
+}
+
+// Connected returns true if the feed is subscribed and receiving
+// messages.
+func (f *Feed) Connected() bool {
+	return f.connected.Load()
+}
+
+// Close disconnects from the feed and waits for the background
+// goroutine to exit.
+func (f *Feed) Close() error {
+
+	if f.closed.Swap(true) {
+		return nil
+	}
+
+	close(f.done)
+
+	f.connLock.Lock()
+	if f.conn != nil {
+		f.conn.Close()
+	}
+	f.connLock.Unlock()
+
+	f.wg.Wait()
+
+	return nil
+}
+
+func (f *Feed) run() {
+
+	defer f.wg.Done()
+
+	var backoff time.Duration
+
+	for !f.closed.Load() {
+
+		err := f.receive()
+
+		// Start the backoff over once a connection has received messages
+		if f.connected.Swap(false) {
+			backoff = 0
+		}
+
+		if f.closed.Load() {
+			break
+		}
+
+		backoff *= 2
+		if backoff < reconnectBackoffMin {
+			backoff = reconnectBackoffMin
+		} else if backoff > reconnectBackoffMax {
+			backoff = reconnectBackoffMax
+		}
+
+		zap.L().Warn("prime feed disconnected - reconnecting", zap.Duration("backoff", backoff), zap.Error(err))
+
+		select {
+		case <-time.After(backoff):
+		case <-f.done:
+			return
+		}
+	}
+}
+
+// receive connects, subscribes, and reads messages until the connection
+// fails or is closed.
+func (f *Feed) receive() error {
+
+	conn, err := f.connect()
+	if err != nil {
+		return err
+	}
+
+	defer f.disconnect(conn)
+
+	for {
+
+		if err := conn.SetReadDeadline(time.Now().Add(readTimeout)); err != nil {
+			return fmt.Errorf("cannot set prime feed read deadline: %w", err)
+		}
+
+		var msg message
+		if err := websocket.JSON.Receive(conn, &msg); err != nil {
+			return fmt.Errorf("cannot read prime feed message: %w", err)
+		}
+
+		if msg.Type == messageTypeError {
+			return fmt.Errorf("prime feed error: %s", msg.Message)
+		}
+
+		f.connected.Store(true)
+
+		if msg.Channel != ChannelOrders {
+			continue
+		}
+
+		for _, e := range msg.Events {
+			for _, u := range e.Orders {
+				f.handler(u)
+			}
+		}
+	}
+}
+
+func (f *Feed) connect() (*websocket.Conn, error) {
+
+	config, err := websocket.NewConfig(f.url, feedOrigin)
+	if err != nil {
+		return nil, fmt.Errorf("cannot configure prime feed: %s - err: %w", f.url, err)
+	}
+
+	conn, err := websocket.DialConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to prime feed: %s - err: %w", f.url, err)
+	}
+
+	f.connLock.Lock()
+	defer f.connLock.Unlock()
+
+	if f.closed.Load() {
+		conn.Close()
+		return nil, errors.New("prime feed closed")
+	}
+
+	f.conn = conn
+
+	now := time.Now()
+	for _, channel := range []string{ChannelHeartbeats, ChannelOrders} {
+		if err := websocket.JSON.Send(conn, newSubscription(channel, f.credentials, now)); err != nil {
+			conn.Close()
+			f.conn = nil
+			return nil, fmt.Errorf("cannot subscribe to prime feed channel: %s - err: %w", channel, err)
+		}
+	}
+
+	zap.L().Info("prime feed connected", zap.String("url", f.url))
+
+	return conn, nil
+}
+
+func (f *Feed) disconnect(conn *websocket.Conn) {
+
+	f.connLock.Lock()
+	defer f.connLock.Unlock()
+
+	conn.Close()
+
+	if f.conn == conn {
+		f.conn = nil
+	}
+}
@@ -0,0 +1,108 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package feed
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	prime "github.com/coinbase-samples/prime-sdk-go"
+	"golang.org/x/net/websocket"
+)
+
+func TestFeedReceivesOrderUpdates(t *testing.T) {
+
+	credentials := &prime.Credentials{
+		AccessKey:    "access",
+		Passphrase:   "passphrase",
+		SigningKey:   "secret",
+		PortfolioId:  "portfolio",
+		SvcAccountId: "svc",
+	}
+
+	subscriptions := make(chan *subscription, 2)
+
+	server := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+
+		for i := 0; i < 2; i++ {
+			var s subscription
+			if err := websocket.JSON.Receive(conn, &s); err != nil {
+				return
+			}
+			subscriptions <- &s
+		}
+
+		websocket.JSON.Send(conn, &message{Channel: ChannelHeartbeats})
+		websocket.JSON.Send(conn, &message{
+			Channel: ChannelOrders,
+			Events: []*event{
+				{Type: "update", Orders: []*OrderUpdate{{OrderId: "order-1", Status: "FILLED"}}},
+			},
+		})
+
+		// Hold the connection open until the client closes it
+		var msg message
+		websocket.JSON.Receive(conn, &msg)
+	}))
+	defer server.Close()
+
+	updates := make(chan *OrderUpdate, 1)
+
+	f := NewFeed("ws"+strings.TrimPrefix(server.URL, "http"), credentials, func(u *OrderUpdate) {
+		updates <- u
+	})
+
+	f.Start()
+	defer f.Close()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case s := <-subscriptions:
+			if expected := sign(credentials.SigningKey, s); s.Signature != expected {
+				t.Errorf("expected: %s - received: %s", expected, s.Signature)
+			}
+			if s.PortfolioId != credentials.PortfolioId || s.ApiKeyId != credentials.SvcAccountId {
+				t.Errorf("unexpected subscription: %+v", s)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected subscription")
+		}
+	}
+
+	select {
+	case u := <-updates:
+		if u.OrderId != "order-1" || u.Status != "FILLED" {
+			t.Errorf("unexpected order update: %+v", u)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected order update")
+	}
+
+	if !f.Connected() {
+		t.Errorf("expected feed to be connected")
+	}
+
+	if err := f.Close(); err != nil {
+		t.Errorf("cannot close feed: %v", err)
+	}
+
+	if f.Connected() {
+		t.Errorf("expected feed to be disconnected")
+	}
+}
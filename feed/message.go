@@ -0,0 +1,108 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package feed
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	prime "github.com/coinbase-samples/prime-sdk-go"
+)
+
+const (
+	ChannelOrders     = "orders"
+	ChannelHeartbeats = "heartbeats"
+
+	messageTypeSubscribe = "subscribe"
+	messageTypeError     = "error"
+)
+
+// OrderUpdate is the state of an order in the portfolio published on
+// the orders channel.
+type OrderUpdate struct {
+	OrderId       string `json:"order_id"`
+	ClientOrderId string `json:"client_order_id"`
+	ProductId     string `json:"product_id"`
+	Side          string `json:"side"`
+	Status        string `json:"status"`
+	CumQty        string `json:"cum_qty"`
+	LeavesQty     string `json:"leaves_qty"`
+	AvgPx         string `json:"avg_px"`
+	Fees          string `json:"fees"`
+}
+
+type event struct {
+	Type   string         `json:"type"`
+	Orders []*OrderUpdate `json:"orders"`
+}
+
+type message struct {
+	Channel     string   `json:"channel"`
+	Type        string   `json:"type"`
+	Message     string   `json:"message"`
+	Timestamp   string   `json:"timestamp"`
+	SequenceNum int64    `json:"sequence_num"`
+	Events      []*event `json:"events"`
+}
+
+type subscription struct {
+	Type        string   `json:"type"`
+	Channel     string   `json:"channel"`
+	AccessKey   string   `json:"access_key"`
+	ApiKeyId    string   `json:"api_key_id"`
+	Timestamp   string   `json:"timestamp"`
+	Passphrase  string   `json:"passphrase"`
+	Signature   string   `json:"signature"`
+	PortfolioId string   `json:"portfolio_id"`
+	ProductIds  []string `json:"product_ids"`
+}
+
+// newSubscription returns a signed subscribe message for the channel.
+func newSubscription(channel string, credentials *prime.Credentials, t time.Time) *subscription {
+
+	s := &subscription{
+		Type:        messageTypeSubscribe,
+		Channel:     channel,
+		AccessKey:   credentials.AccessKey,
+		ApiKeyId:    credentials.SvcAccountId,
+		Timestamp:   strconv.FormatInt(t.Unix(), 10),
+		Passphrase:  credentials.Passphrase,
+		PortfolioId: credentials.PortfolioId,
+		ProductIds:  []string{},
+	}
+
+	s.Signature = sign(credentials.SigningKey, s)
+
+	return s
+}
+
+// sign returns the base64 encoded HMAC-SHA256 of the channel, access
+// key, service account id, timestamp, portfolio id, and product ids.
+func sign(signingKey string, s *subscription) string {
+	h := hmac.New(sha256.New, []byte(signingKey))
+	h.Write([]byte(s.Channel))
+	h.Write([]byte(s.AccessKey))
+	h.Write([]byte(s.ApiKeyId))
+	h.Write([]byte(s.Timestamp))
+	h.Write([]byte(s.PortfolioId))
+	h.Write([]byte(strings.Join(s.ProductIds, "")))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
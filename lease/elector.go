@@ -0,0 +1,126 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lease
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Elector renews the lease from a background goroutine. Leadership is
+// assumed to end one TTL after the start of the last successful renewal,
+// which is no later than the lease expires for the other replicas, so a
+// leader that cannot renew stops leading before a standby can take over.
+type Elector struct {
+	lease      Lease
+	ttl        time.Duration
+	onChange   func(leader bool)
+	validUntil atomic.Int64
+	leader     atomic.Bool
+	done       chan struct{}
+	wg         sync.WaitGroup
+}
+
+// NewElector returns an elector that renews the lease every third of
+// the TTL and calls onChange when leadership is gained or lost.
+func NewElector(lease Lease, ttl time.Duration, onChange func(leader bool)) *Elector {
+	return &Elector{
+		lease:    lease,
+		ttl:      ttl,
+		onChange: onChange,
+		done:     make(chan struct{}),
+	}
+}
+
+// Start attempts to acquire the lease and then continues renewing it in
+// the background.
+func (e *Elector) Start() {
+
+	e.renew()
+
+	e.wg.Add(1)
+	go e.run()
+}
+
+// Stop stops renewing and releases the lease if it is held.
+func (e *Elector) Stop() error {
+
+	close(e.done)
+	e.wg.Wait()
+
+	leader := e.IsLeader()
+
+	e.validUntil.Store(0)
+
+	if !leader {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.ttl)
+	defer cancel()
+
+	return e.lease.Release(ctx)
+}
+
+// IsLeader returns true if this process holds an unexpired lease.
+func (e *Elector) IsLeader() bool {
+	return time.Now().UnixNano() < e.validUntil.Load()
+}
+
+func (e *Elector) run() {
+
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			e.renew()
+		case <-e.done:
+			return
+		}
+	}
+}
+
+func (e *Elector) renew() {
+
+	start := time.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.ttl/3)
+	defer cancel()
+
+	acquired, err := e.lease.Acquire(ctx)
+	if err != nil {
+		// Keep leading until the current lease expires
+		zap.L().Error("cannot renew lease", zap.Error(err))
+	} else if acquired {
+		e.validUntil.Store(start.Add(e.ttl).UnixNano())
+	} else {
+		e.validUntil.Store(0)
+	}
+
+	leader := e.IsLeader()
+	if e.leader.Swap(leader) != leader && e.onChange != nil {
+		e.onChange(leader)
+	}
+}
@@ -0,0 +1,39 @@
+//go:build !unix
+
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lease
+
+import (
+	"context"
+	"errors"
+)
+
+// FileLease is not supported on this platform and never acquires.
+type FileLease struct{}
+
+func NewFileLease(path, holder string) *FileLease {
+	return &FileLease{}
+}
+
+func (l *FileLease) Acquire(ctx context.Context) (bool, error) {
+	return false, errors.New("file lease is not supported on this platform")
+}
+
+func (l *FileLease) Release(ctx context.Context) error {
+	return nil
+}
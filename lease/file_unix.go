@@ -0,0 +1,95 @@
+//go:build unix
+
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lease
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+)
+
+// FileLease is a lease held with an exclusive flock on a file. The lock
+// is released by the kernel if the process exits, so the lease does
+// not expire while the holder is running. It only coordinates processes
+// that share the file system.
+type FileLease struct {
+	path   string
+	holder string
+	file   *os.File
+	mu     sync.Mutex
+}
+
+func NewFileLease(path, holder string) *FileLease {
+	return &FileLease{path: path, holder: holder}
+}
+
+func (l *FileLease) Acquire(ctx context.Context) (bool, error) {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file != nil {
+		return true, nil
+	}
+
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return false, fmt.Errorf("cannot open lease file: %s - err: %w", l.path, err)
+	}
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return false, nil
+		}
+		return false, fmt.Errorf("cannot lock lease file: %s - err: %w", l.path, err)
+	}
+
+	// Record the holder to help operators see which process leads
+	if err := f.Truncate(0); err == nil {
+		f.WriteAt([]byte(l.holder+"\n"), 0)
+	}
+
+	l.file = f
+
+	return true, nil
+}
+
+func (l *FileLease) Release(ctx context.Context) error {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+
+	f := l.file
+	l.file = nil
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_UN); err != nil {
+		f.Close()
+		return fmt.Errorf("cannot unlock lease file: %s - err: %w", l.path, err)
+	}
+
+	return f.Close()
+}
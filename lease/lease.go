@@ -0,0 +1,89 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lease
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+
+	"github.com/coinbase-samples/prime-liquidator-go/config"
+	"github.com/google/uuid"
+
+	// Registers the pgx driver for Postgres table leases
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+const (
+	BackendFile  = "file"
+	BackendTable = "table"
+)
+
+// Lease grants leadership to a single holder at a time so that only one
+// replica submits orders and conversions.
+type Lease interface {
+	// Acquire acquires the lease, or renews it if it is already held,
+	// and returns true if this process is the holder.
+	Acquire(ctx context.Context) (bool, error)
+
+	// Release gives up the lease so a standby can take over without
+	// waiting for it to expire.
+	Release(ctx context.Context) error
+}
+
+// NewLease returns the lease for the configured backend. If the backend
+// is not set, nil is returned and the process always leads.
+func NewLease(config *config.AppConfig) (Lease, error) {
+
+	switch config.LeaseBackend {
+	case "":
+		return nil, nil
+
+	case BackendFile:
+		return NewFileLease(config.LeaseFilePath, HolderId()), nil
+
+	case BackendTable:
+		if !driverRegistered(config.LeaseDatabaseDriver) {
+			return nil, fmt.Errorf("lease database driver is not linked: %s - available: %v", config.LeaseDatabaseDriver, sql.Drivers())
+		}
+		db, err := sql.Open(config.LeaseDatabaseDriver, config.LeaseDatabaseUrl)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open lease database: %s - err: %w", config.LeaseDatabaseDriver, err)
+		}
+		table := NewSqlTable(db, config.LeaseTableName)
+		return NewTableLease(table, config.LeaseName, HolderId(), config.LeaseTtl()), nil
+	}
+
+	return nil, fmt.Errorf("unknown lease backend: %s", config.LeaseBackend)
+}
+
+func driverRegistered(name string) bool {
+	for _, d := range sql.Drivers() {
+		if d == name {
+			return true
+		}
+	}
+	return false
+}
+
+// HolderId returns an id for this process that is unique across hosts
+// and restarts.
+func HolderId() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.New().String()[:8])
+}
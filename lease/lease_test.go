@@ -0,0 +1,151 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lease
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestMemoryTableClaim(t *testing.T) {
+
+	table := NewMemoryTable()
+
+	ctx := context.Background()
+	now := time.Now()
+
+	cases := []struct {
+		description string
+		holder      string
+		now         time.Time
+		expected    bool
+	}{
+		{description: "TestMemoryTableClaimUnheld", holder: "a", now: now, expected: true},
+		{description: "TestMemoryTableClaimHeldByOther", holder: "b", now: now.Add(10 * time.Second), expected: false},
+		{description: "TestMemoryTableClaimRenew", holder: "a", now: now.Add(20 * time.Second), expected: true},
+		{description: "TestMemoryTableClaimBeforeExpiry", holder: "b", now: now.Add(49 * time.Second), expected: false},
+		{description: "TestMemoryTableClaimExpired", holder: "b", now: now.Add(50 * time.Second), expected: true},
+	}
+
+	for _, tt := range cases {
+		result, err := table.Claim(ctx, "liquidator", tt.holder, tt.now, tt.now.Add(30*time.Second))
+		if err != nil {
+			t.Fatalf("test: %s - cannot claim: %v", tt.description, err)
+		}
+		if result != tt.expected {
+			t.Errorf("test: %s - expected: %t - received: %t", tt.description, tt.expected, result)
+		}
+	}
+}
+
+func TestTableLeaseRelease(t *testing.T) {
+
+	table := NewMemoryTable()
+	ctx := context.Background()
+
+	leader := NewTableLease(table, "liquidator", "a", time.Minute)
+	standby := NewTableLease(table, "liquidator", "b", time.Minute)
+
+	if ok, _ := leader.Acquire(ctx); !ok {
+		t.Fatalf("expected leader to acquire")
+	}
+
+	if ok, _ := standby.Acquire(ctx); ok {
+		t.Fatalf("expected standby not to acquire")
+	}
+
+	if err := leader.Release(ctx); err != nil {
+		t.Fatalf("cannot release: %v", err)
+	}
+
+	if ok, _ := standby.Acquire(ctx); !ok {
+		t.Errorf("expected standby to acquire after release")
+	}
+}
+
+func TestFileLease(t *testing.T) {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("file lease is not supported")
+	}
+
+	path := filepath.Join(t.TempDir(), "liquidator.lock")
+	ctx := context.Background()
+
+	leader := NewFileLease(path, "a")
+	standby := NewFileLease(path, "b")
+
+	if ok, err := leader.Acquire(ctx); !ok || err != nil {
+		t.Fatalf("expected leader to acquire - err: %v", err)
+	}
+
+	if ok, err := standby.Acquire(ctx); ok || err != nil {
+		t.Fatalf("expected standby not to acquire - err: %v", err)
+	}
+
+	if err := leader.Release(ctx); err != nil {
+		t.Fatalf("cannot release: %v", err)
+	}
+
+	if ok, err := standby.Acquire(ctx); !ok || err != nil {
+		t.Errorf("expected standby to acquire after release - err: %v", err)
+	}
+
+	standby.Release(ctx)
+}
+
+func TestElector(t *testing.T) {
+
+	table := NewMemoryTable()
+
+	changes := make(chan bool, 2)
+
+	leader := NewElector(NewTableLease(table, "liquidator", "a", time.Minute), time.Minute, func(l bool) { changes <- l })
+	standby := NewElector(NewTableLease(table, "liquidator", "b", time.Minute), time.Minute, nil)
+
+	leader.Start()
+	standby.Start()
+
+	if !leader.IsLeader() || standby.IsLeader() {
+		t.Fatalf("expected a single leader")
+	}
+
+	if err := leader.Stop(); err != nil {
+		t.Fatalf("cannot stop elector: %v", err)
+	}
+
+	if leader.IsLeader() {
+		t.Errorf("expected leadership to end on stop")
+	}
+
+	standby.renew()
+
+	if !standby.IsLeader() {
+		t.Errorf("expected standby to lead after release")
+	}
+
+	if err := standby.Stop(); err != nil {
+		t.Fatalf("cannot stop elector: %v", err)
+	}
+
+	if changed := <-changes; !changed {
+		t.Errorf("expected leader change notification")
+	}
+}
@@ -0,0 +1,153 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lease
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Table stores leases as rows keyed by name with the holder and expiry.
+// Claim must be a single conditional write so that only one holder can
+// succeed, e.g., an upsert with a WHERE clause in Postgres or a PutItem
+// with a condition expression in DynamoDB.
+type Table interface {
+	// Claim sets the holder and expiry if the lease does not exist, has
+	// expired, or is already held by the holder, and returns true if the
+	// write happened.
+	Claim(ctx context.Context, name, holder string, now, expiry time.Time) (bool, error)
+
+	// Release expires the lease if it is held by the holder.
+	Release(ctx context.Context, name, holder string) error
+}
+
+// TableLease is a lease with an expiry that is held in a shared table.
+// The holder must renew it before it expires.
+type TableLease struct {
+	table  Table
+	name   string
+	holder string
+	ttl    time.Duration
+}
+
+func NewTableLease(table Table, name, holder string, ttl time.Duration) *TableLease {
+	return &TableLease{table: table, name: name, holder: holder, ttl: ttl}
+}
+
+func (l *TableLease) Acquire(ctx context.Context) (bool, error) {
+	now := time.Now().UTC()
+	return l.table.Claim(ctx, l.name, l.holder, now, now.Add(l.ttl))
+}
+
+func (l *TableLease) Release(ctx context.Context) error {
+	return l.table.Release(ctx, l.name, l.holder)
+}
+
+// SqlTable is a Table in a SQL database using Postgres syntax. The pgx
+// driver for Postgres is linked into the binary. The table is:
+//
+//	CREATE TABLE liquidator_leases (
+//	  name       TEXT PRIMARY KEY,
+//	  holder     TEXT NOT NULL,
+//	  expires_at BIGINT NOT NULL
+//	);
+type SqlTable struct {
+	db    *sql.DB
+	table string
+}
+
+func NewSqlTable(db *sql.DB, table string) *SqlTable {
+	return &SqlTable{db: db, table: table}
+}
+
+func (t *SqlTable) Claim(ctx context.Context, name, holder string, now, expiry time.Time) (bool, error) {
+
+	query := fmt.Sprintf(
+		`INSERT INTO %[1]s (name, holder, expires_at) VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE SET holder = EXCLUDED.holder, expires_at = EXCLUDED.expires_at
+		WHERE %[1]s.holder = EXCLUDED.holder OR %[1]s.expires_at <= $4`,
+		t.table,
+	)
+
+	res, err := t.db.ExecContext(ctx, query, name, holder, expiry.UnixMilli(), now.UnixMilli())
+	if err != nil {
+		return false, fmt.Errorf("cannot claim lease: %s - err: %w", name, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("cannot claim lease: %s - err: %w", name, err)
+	}
+
+	return rows == 1, nil
+}
+
+func (t *SqlTable) Release(ctx context.Context, name, holder string) error {
+
+	query := fmt.Sprintf(`UPDATE %s SET expires_at = 0 WHERE name = $1 AND holder = $2`, t.table)
+
+	if _, err := t.db.ExecContext(ctx, query, name, holder); err != nil {
+		return fmt.Errorf("cannot release lease: %s - err: %w", name, err)
+	}
+
+	return nil
+}
+
+// MemoryTable is an in-memory Table that stands in for a database in
+// tests and when all of the replicas run in one process.
+type MemoryTable struct {
+	mu     sync.Mutex
+	leases map[string]memoryLease
+}
+
+type memoryLease struct {
+	holder string
+	expiry time.Time
+}
+
+func NewMemoryTable() *MemoryTable {
+	return &MemoryTable{leases: make(map[string]memoryLease)}
+}
+
+func (t *MemoryTable) Claim(ctx context.Context, name, holder string, now, expiry time.Time) (bool, error) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if current, found := t.leases[name]; found && current.holder != holder && current.expiry.After(now) {
+		return false, nil
+	}
+
+	t.leases[name] = memoryLease{holder: holder, expiry: expiry}
+
+	return true, nil
+}
+
+func (t *MemoryTable) Release(ctx context.Context, name, holder string) error {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if current, found := t.leases[name]; found && current.holder == holder {
+		delete(t.leases, name)
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+/**
+ * Copyright 2023-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lease
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/config"
+	_ "modernc.org/sqlite"
+)
+
+const testLeaseTableSchema = `CREATE TABLE %s (
+	name       TEXT PRIMARY KEY,
+	holder     TEXT NOT NULL,
+	expires_at BIGINT NOT NULL
+)`
+
+// TestSqlTable runs the Postgres queries against SQLite, which supports
+// the same upsert syntax, and against Postgres with the linked pgx
+// driver if LEASE_TEST_DATABASE_URL is set.
+func TestSqlTable(t *testing.T) {
+
+	cases := []struct {
+		description string
+		driver      string
+		url         string
+	}{
+		{description: "TestSqlTableSqlite", driver: "sqlite", url: ":memory:"},
+		{description: "TestSqlTablePostgres", driver: "pgx", url: os.Getenv("LEASE_TEST_DATABASE_URL")},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			if len(tt.url) == 0 {
+				t.Skip("LEASE_TEST_DATABASE_URL is not set")
+			}
+
+			db, err := sql.Open(tt.driver, tt.url)
+			if err != nil {
+				t.Fatalf("test: %s - cannot open database: %v", tt.description, err)
+			}
+			defer db.Close()
+
+			// Each SQLite memory connection is a separate database
+			db.SetMaxOpenConns(1)
+
+			name := fmt.Sprintf("liquidator_leases_%d", time.Now().UnixNano())
+
+			if _, err := db.Exec(fmt.Sprintf(testLeaseTableSchema, name)); err != nil {
+				t.Fatalf("test: %s - cannot create table: %v", tt.description, err)
+			}
+			defer db.Exec(fmt.Sprintf("DROP TABLE %s", name))
+
+			testTable(t, tt.description, NewSqlTable(db, name))
+		})
+	}
+}
+
+// testTable checks that only one holder can claim the lease until it
+// expires or is released.
+func testTable(t *testing.T, description string, table Table) {
+
+	ctx := context.Background()
+	now := time.Now()
+
+	claims := []struct {
+		holder   string
+		now      time.Time
+		expected bool
+	}{
+		{holder: "a", now: now, expected: true},
+		{holder: "b", now: now.Add(10 * time.Second), expected: false},
+		{holder: "a", now: now.Add(20 * time.Second), expected: true},
+		{holder: "b", now: now.Add(49 * time.Second), expected: false},
+		{holder: "b", now: now.Add(50 * time.Second), expected: true},
+	}
+
+	for i, c := range claims {
+		result, err := table.Claim(ctx, "liquidator", c.holder, c.now, c.now.Add(30*time.Second))
+		if err != nil {
+			t.Fatalf("test: %s - claim: %d - cannot claim: %v", description, i, err)
+		}
+		if result != c.expected {
+			t.Errorf("test: %s - claim: %d - expected: %t - received: %t", description, i, c.expected, result)
+		}
+	}
+
+	later := now.Add(60 * time.Second)
+
+	// Only the holder can release the lease
+	if err := table.Release(ctx, "liquidator", "a"); err != nil {
+		t.Fatalf("test: %s - cannot release: %v", description, err)
+	}
+
+	if result, _ := table.Claim(ctx, "liquidator", "a", later, later.Add(30*time.Second)); result {
+		t.Errorf("test: %s - expected a release by another holder to be ignored", description)
+	}
+
+	if err := table.Release(ctx, "liquidator", "b"); err != nil {
+		t.Fatalf("test: %s - cannot release: %v", description, err)
+	}
+
+	if result, _ := table.Claim(ctx, "liquidator", "a", later, later.Add(30*time.Second)); !result {
+		t.Errorf("test: %s - expected the lease to be claimed after release", description)
+	}
+}
+
+func TestNewLeaseDriver(t *testing.T) {
+
+	cases := []struct {
+		description string
+		driver      string
+		expectErr   bool
+	}{
+		{description: "TestNewLeaseDriverPgx", driver: "pgx"},
+		{description: "TestNewLeaseDriverNotLinked", driver: "mysql", expectErr: true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			c := &config.AppConfig{
+				LeaseBackend:        BackendTable,
+				LeaseName:           "liquidator",
+				LeaseTtlInSeconds:   "30",
+				LeaseDatabaseDriver: tt.driver,
+				LeaseDatabaseUrl:    "postgres://liquidator@localhost:5432/liquidator",
+				LeaseTableName:      "liquidator_leases",
+			}
+
+			l, err := NewLease(c)
+
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("test: %s - expected error", tt.description)
+				}
+				return
+			}
+
+			if err != nil || l == nil {
+				t.Errorf("test: %s - expected a lease - received: %v", tt.description, err)
+			}
+		})
+	}
+}
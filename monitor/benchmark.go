@@ -0,0 +1,74 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// marketTwap is the time weighted average of the Exchange price sampled
+// while an order is working. Each price is weighted by the time until the
+// next sample. The first price is the Exchange price at decision time.
+type marketTwap struct {
+	value      decimal.Decimal
+	seconds    decimal.Decimal
+	lastPrice  decimal.Decimal
+	lastSample time.Time
+}
+
+func newMarketTwap(price decimal.Decimal, now time.Time) *marketTwap {
+	return &marketTwap{lastPrice: price, lastSample: now}
+}
+
+func (m *marketTwap) sample(price decimal.Decimal, now time.Time) {
+
+	if elapsed := now.Sub(m.lastSample); elapsed > 0 {
+		seconds := decimal.NewFromFloat(elapsed.Seconds())
+		m.value = m.value.Add(m.lastPrice.Mul(seconds))
+		m.seconds = m.seconds.Add(seconds)
+	}
+
+	m.lastPrice = price
+	m.lastSample = now
+}
+
+// price returns the average price or the last price if no time has
+// elapsed between samples.
+func (m *marketTwap) price() decimal.Decimal {
+	if !m.seconds.IsPositive() {
+		return m.lastPrice
+	}
+	return m.value.DivRound(m.seconds, 8)
+}
+
+// sampleMarket samples the Exchange price of the TWAP order until it
+// expires. The sample is skipped if the price cannot be looked up.
+func (l *Liquidator) sampleMarket(tracked *trackedOrder, now time.Time) {
+
+	if tracked.market == nil || now.After(tracked.expiry) {
+		return
+	}
+
+	price, err := l.call.ExchangeCurrentProductPrice(tracked.productId)
+	if err != nil {
+		return
+	}
+
+	tracked.market.sample(price, now)
+}
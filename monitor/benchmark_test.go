@@ -0,0 +1,55 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestMarketTwap(t *testing.T) {
+
+	start := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		description string
+		samples     []int64
+		expected    string
+	}{
+		{description: "TestMarketTwapNoSamples", expected: "100"},
+		{description: "TestMarketTwapOneSample", samples: []int64{110}, expected: "100"},
+		{description: "TestMarketTwapWeighted", samples: []int64{110, 90}, expected: "105"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			m := newMarketTwap(decimal.NewFromInt(100), start)
+
+			// Samples are one minute apart, so the weights are equal
+			for i, price := range tt.samples {
+				m.sample(decimal.NewFromInt(price), start.Add(time.Duration(i+1)*time.Minute))
+			}
+
+			if result := m.price(); !result.Equal(decimal.RequireFromString(tt.expected)) {
+				t.Errorf("test: %s - expected: %s - received: %s", tt.description, tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/notify"
+	"go.uber.org/zap"
+)
+
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half_open"
+)
+
+// BreakerStatus is a point in time view of the circuit breaker.
+type BreakerStatus struct {
+	State     BreakerState `json:"state"`
+	Failures  int          `json:"failures"`
+	OpenUntil *time.Time   `json:"open_until,omitempty"`
+}
+
+// breaker halts order submission after consecutive failures. Once the
+// cool down elapses, a single trial submission is allowed. If the trial
+// succeeds the breaker closes, otherwise it opens again.
+type breaker struct {
+	lock      sync.Mutex
+	threshold int
+	coolDown  time.Duration
+	failures  int
+	state     BreakerState
+	openedAt  time.Time
+	trial     bool
+}
+
+func newBreaker(threshold int, coolDown time.Duration) *breaker {
+	return &breaker{
+		threshold: threshold,
+		coolDown:  coolDown,
+		state:     BreakerClosed,
+	}
+}
+
+// allow returns true if a submission may be attempted.
+func (b *breaker) allow(now time.Time) bool {
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	switch b.state {
+	case BreakerClosed:
+		return true
+	case BreakerOpen:
+		if now.Sub(b.openedAt) < b.coolDown {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		orderBreakerState.Set(string(BreakerHalfOpen))
+	}
+
+	if b.trial {
+		return false
+	}
+
+	b.trial = true
+	return true
+}
+
+// success closes the breaker and returns true if it was not closed.
+func (b *breaker) success() (closed bool) {
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	closed = b.state != BreakerClosed
+
+	b.state = BreakerClosed
+	b.failures = 0
+	b.trial = false
+
+	return
+}
+
+// failure counts a failed submission and returns true if the breaker
+// opened as a result.
+func (b *breaker) failure(now time.Time) (opened bool) {
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.failures++
+
+	if b.state == BreakerHalfOpen || (b.state == BreakerClosed && b.threshold > 0 && b.failures >= b.threshold) {
+		b.state = BreakerOpen
+		b.openedAt = now
+		opened = true
+	}
+
+	b.trial = false
+
+	return
+}
+
+// release ends a trial that did not reach Prime.
+func (b *breaker) release() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.trial = false
+}
+
+func (b *breaker) status() BreakerStatus {
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	s := BreakerStatus{State: b.state, Failures: b.failures}
+
+	if b.state == BreakerOpen {
+		openUntil := b.openedAt.Add(b.coolDown)
+		s.OpenUntil = &openUntil
+	}
+
+	return s
+}
+
+// orderFailed counts the failure and raises an alert if the breaker opens.
+func (l *Liquidator) orderFailed(err error) {
+
+	if !l.breaker.failure(time.Now()) {
+		return
+	}
+
+	status := l.breaker.status()
+
+	zap.L().Error(
+		"order breaker opened - halting order submission",
+		zap.Int("failures", status.Failures),
+		zap.Timep("openUntil", status.OpenUntil),
+		zap.Error(err),
+	)
+
+	orderBreakerState.Set(string(BreakerOpen))
+	orderBreakerOpened.Add(1)
+
+	e := notify.NewEvent(notify.EventBreakerOpened).WithError(err)
+	e.Message = fmt.Sprintf("order submission halted until %s after %d failures", status.OpenUntil.Format(time.RFC3339), status.Failures)
+	l.notifier.Notify(e)
+}
+
+// orderSucceeded resets the failure count and notifies if the breaker
+// was not closed.
+func (l *Liquidator) orderSucceeded() {
+
+	if !l.breaker.success() {
+		return
+	}
+
+	zap.L().Info("order breaker closed - resuming order submission")
+
+	orderBreakerState.Set(string(BreakerClosed))
+
+	l.notifier.Notify(notify.NewEvent(notify.EventBreakerClosed))
+}
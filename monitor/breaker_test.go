@@ -0,0 +1,69 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+
+	now := time.Now()
+
+	b := newBreaker(2, time.Minute)
+
+	if b.failure(now) {
+		t.Fatalf("expected breaker to stay closed after one failure")
+	}
+
+	if !b.failure(now) {
+		t.Fatalf("expected breaker to open after two failures")
+	}
+
+	if b.allow(now.Add(30 * time.Second)) {
+		t.Errorf("expected breaker to block during cool down")
+	}
+
+	if !b.allow(now.Add(time.Minute)) {
+		t.Errorf("expected breaker to allow a trial after cool down")
+	}
+
+	if v := orderBreakerState.Value(); v != string(BreakerHalfOpen) {
+		t.Errorf("expected: %s - received: %s", BreakerHalfOpen, v)
+	}
+
+	if b.allow(now.Add(time.Minute)) {
+		t.Errorf("expected breaker to allow a single trial")
+	}
+
+	if !b.failure(now.Add(time.Minute)) {
+		t.Errorf("expected breaker to open after a failed trial")
+	}
+
+	if !b.allow(now.Add(2 * time.Minute)) {
+		t.Errorf("expected breaker to allow a trial after the second cool down")
+	}
+
+	if !b.success() {
+		t.Errorf("expected breaker to close after a successful trial")
+	}
+
+	if s := b.status(); s.State != BreakerClosed || s.Failures != 0 {
+		t.Errorf("expected closed breaker - received: %+v", s)
+	}
+}
@@ -17,6 +17,8 @@
 package caller
 
 import (
+	"time"
+
 	prime "github.com/coinbase-samples/prime-sdk-go"
 	"github.com/shopspring/decimal"
 )
@@ -24,24 +26,56 @@ import (
 type Caller interface {
 	ExchangeCurrentProductPrice(productId string) (decimal.Decimal, error)
 	PrimeDescribeTradingWallets() (WalletLookup, error)
+	PrimeDescribeVaultWallets(symbols []string) ([]*prime.Wallet, error)
+	PrimeDescribeWalletBalance(walletId string) (*prime.Balance, error)
+	PrimeDescribeWalletTransactions(walletId string, start time.Time) ([]*prime.Transaction, error)
 	PrimeDescribeProducts() (ProductLookup, error)
 	PrimeDescribeTradingBalances() ([]*prime.Balance, error)
-	PrimeCreateConversion(sourceWallet, destinationWallet *prime.Wallet, amount decimal.Decimal) error
+	PrimeDescribeOrder(orderId string) (*prime.Order, error)
+	PrimeDescribeOrderFills(orderId string) ([]*prime.OrderFill, error)
+	PrimeDescribeOpenOrders(productId string) ([]*prime.Order, error)
+	PrimeDescribeOrders(status, productId string, start time.Time) ([]*prime.Order, error)
+	PrimeDescribeActivity(activityId string) (*prime.Activity, error)
+	PrimeDescribePendingTransactionActivities(symbols []string, start time.Time) ([]*prime.Activity, error)
+	PrimeDescribeTransaction(transactionId string) (*prime.Transaction, error)
+
+	PrimeCreateConversion(
+		sourceWallet,
+		destinationWallet *prime.Wallet,
+		amount decimal.Decimal,
+		digits int32,
+	) (*prime.CreateConversionResponse, error)
+
+	PrimeCreateWalletTransfer(
+		sourceWallet,
+		destinationWallet *prime.Wallet,
+		amount decimal.Decimal,
+	) (*prime.CreateWalletTransferResponse, error)
+
+	PrimeCreateWalletWithdrawal(
+		sourceWallet *prime.Wallet,
+		amount decimal.Decimal,
+		paymentMethodId,
+		address string,
+	) (*prime.CreateWalletWithdrawalResponse, error)
 
 	PrimeCreateTwapOrder(
 		productId string,
 		value,
 		orderSize,
 		limitPrice decimal.Decimal,
+		duration time.Duration,
 		asset *prime.Balance,
-	) error
+	) (*prime.CreateOrderResponse, error)
 
 	PrimeCreateMarketOrder(
 		productId string,
 		value,
 		orderSize decimal.Decimal,
 		asset *prime.Balance,
-	) error
+	) (*prime.CreateOrderResponse, error)
+
+	PrimeCancelOrder(orderId string) error
 
 	PrimeCalculateOrderSize(product *prime.Product, amount, holds decimal.Decimal) (orderSize decimal.Decimal, err error)
 }
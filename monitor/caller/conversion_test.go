@@ -0,0 +1,114 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package caller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/coinbase-samples/prime-liquidator-go/config"
+	prime "github.com/coinbase-samples/prime-sdk-go"
+	"github.com/jellydator/ttlcache/v2"
+	"github.com/shopspring/decimal"
+	"golang.org/x/time/rate"
+)
+
+func TestPrimeCreateConversionDedupe(t *testing.T) {
+
+	var keys []string
+
+	statusCodes := []int{http.StatusServiceUnavailable, http.StatusOK}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request prime.CreateConversionRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		keys = append(keys, request.IdempotencyKey)
+
+		w.WriteHeader(statusCodes[len(keys)-1])
+		json.NewEncoder(w).Encode(&prime.CreateConversionResponse{ActivityId: "activity-1"})
+	}))
+	defer server.Close()
+
+	ac := testConversionCaller(server.URL)
+
+	source := &prime.Wallet{Id: "usdc-wallet", Symbol: "USDC"}
+	destination := &prime.Wallet{Id: "usd-wallet", Symbol: "USD"}
+	amount := decimal.RequireFromString("100.005")
+
+	// The first attempt fails with an unknown outcome
+	if _, err := ac.PrimeCreateConversion(source, destination, amount, 2); !errors.Is(err, ErrTransient) {
+		t.Fatalf("expected transient error - received: %v", err)
+	}
+
+	response, err := ac.PrimeCreateConversion(source, destination, amount, 2)
+	if err != nil {
+		t.Fatalf("cannot create conversion: %v", err)
+	}
+
+	if response.ActivityId != "activity-1" {
+		t.Errorf("expected: activity-1 - received: %s", response.ActivityId)
+	}
+
+	if len(keys) != 2 || keys[0] != keys[1] {
+		t.Errorf("expected the idempotency key to be reused - received: %v", keys)
+	}
+
+	var duplicate *DuplicateConversionError
+	if _, err = ac.PrimeCreateConversion(source, destination, amount, 2); !errors.As(err, &duplicate) {
+		t.Fatalf("expected duplicate conversion - received: %v", err)
+	}
+
+	if duplicate.ActivityId != "activity-1" || len(keys) != 2 {
+		t.Errorf("expected duplicate not to be sent - received: %+v - requests: %d", duplicate, len(keys))
+	}
+}
+
+func TestGenerateIdempotencyKey(t *testing.T) {
+
+	key := generateIdempotencyKey("usdc-wallet", "usd-wallet", "100", "1710000000")
+
+	if key != generateIdempotencyKey("usdc-wallet", "usd-wallet", "100", "1710000000") {
+		t.Errorf("expected the same key for the same parameters")
+	}
+
+	if key == generateIdempotencyKey("usdc-wallet", "usd-wallet", "100", "1710000600") {
+		t.Errorf("expected a different key for a different time bucket")
+	}
+
+	if len(key) != 36 {
+		t.Errorf("expected a UUID - received: %s", key)
+	}
+}
+
+func testConversionCaller(baseUrl string) apiCall {
+
+	credentials := &prime.Credentials{PortfolioId: "portfolio", SigningKey: "secret"}
+
+	return apiCall{
+		config: &config.AppConfig{
+			PrimeClient:               prime.NewClient(credentials, http.Client{}).SetBaseUrl(baseUrl),
+			PrimeCallTimeoutInSeconds: "5",
+		},
+		ordersCache:  ttlcache.NewCache(),
+		portfolioId:  credentials.PortfolioId,
+		retry:        retryPolicy{maxAttempts: 1},
+		primeLimiter: rate.NewLimiter(rate.Inf, 1),
+	}
+}
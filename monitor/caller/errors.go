@@ -0,0 +1,141 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package caller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/coinbase-samples/core-go"
+	"github.com/coinbase-samples/prime-liquidator-go/exchange"
+)
+
+// ErrZeroConversionAmount is returned when the conversion amount rounds
+// down to zero and no request is sent.
+var ErrZeroConversionAmount = errors.New("conversion amount rounds to zero")
+
+// DuplicateOrderError is returned when an order with the same client
+// order id was recently submitted and the order is not sent again.
+type DuplicateOrderError struct {
+	ClientOrderId string
+}
+
+func (e *DuplicateOrderError) Error() string {
+	return fmt.Sprintf("duplicate order - client order id: %s", e.ClientOrderId)
+}
+
+// DuplicateConversionError is returned when the same amount was recently
+// converted between the same wallets and the conversion is not sent
+// again.
+type DuplicateConversionError struct {
+	IdempotencyKey string
+	ActivityId     string
+}
+
+func (e *DuplicateConversionError) Error() string {
+	return fmt.Sprintf("duplicate conversion - idempotency key: %s - activity id: %s", e.IdempotencyKey, e.ActivityId)
+}
+
+// Error kinds used to classify failed Prime and Exchange calls. Use
+// errors.Is to test a returned error against a kind.
+var (
+	ErrAuth           = errors.New("authentication failed")
+	ErrRateLimited    = errors.New("rate limited")
+	ErrTransient      = errors.New("transient failure")
+	ErrRejected       = errors.New("request rejected")
+	ErrInvalidProduct = errors.New("invalid product")
+)
+
+// CallError is a failed Prime or Exchange call along with its kind and
+// the HTTP status code, if one was received.
+type CallError struct {
+	Kind       error
+	StatusCode int
+	Err        error
+}
+
+func (e *CallError) Error() string {
+	return fmt.Sprintf("%v - status: %d - %v", e.Kind, e.StatusCode, e.Err)
+}
+
+func (e *CallError) Unwrap() error {
+	return e.Err
+}
+
+func (e *CallError) Is(target error) bool {
+	return e.Kind == target
+}
+
+// classify wraps the error in a CallError based on the Prime or Exchange
+// response. Errors without a response, such as timeouts and connection
+// failures, are transient.
+func classify(err error) error {
+
+	if err == nil {
+		return nil
+	}
+
+	var callErr *CallError
+	if errors.As(err, &callErr) {
+		return err
+	}
+
+	var statusCode int
+
+	var apiErr *core.ApiError
+	var exchangeErr *exchange.HttpError
+
+	if errors.As(err, &apiErr) {
+		statusCode = apiErr.CodeReceived
+	} else if errors.As(err, &exchangeErr) {
+		statusCode = exchangeErr.StatusCode
+		if statusCode == http.StatusNotFound {
+			return &CallError{Kind: ErrInvalidProduct, StatusCode: statusCode, Err: err}
+		}
+	}
+
+	return &CallError{Kind: kindOf(statusCode, err), StatusCode: statusCode, Err: err}
+}
+
+func kindOf(statusCode int, err error) error {
+
+	switch {
+	case statusCode == http.StatusUnauthorized, statusCode == http.StatusForbidden:
+		return ErrAuth
+	case statusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case statusCode == http.StatusRequestTimeout, statusCode >= http.StatusInternalServerError:
+		return ErrTransient
+	case statusCode >= http.StatusBadRequest:
+		return ErrRejected
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) {
+		return ErrTransient
+	}
+
+	// The SDK reports transport failures without a status code
+	if statusCode == 0 {
+		return ErrTransient
+	}
+
+	return ErrRejected
+}
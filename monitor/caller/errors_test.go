@@ -0,0 +1,81 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package caller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/coinbase-samples/core-go"
+	"github.com/coinbase-samples/prime-liquidator-go/exchange"
+)
+
+func TestClassify(t *testing.T) {
+
+	cases := []struct {
+		description string
+		err         error
+		expected    error
+	}{
+		{
+			description: "TestClassifyUnauthorized",
+			err:         &core.ApiError{CodeReceived: 401},
+			expected:    ErrAuth,
+		},
+		{
+			description: "TestClassifyRateLimited",
+			err:         fmt.Errorf("unable to create twap order %w", &core.ApiError{CodeReceived: 429}),
+			expected:    ErrRateLimited,
+		},
+		{
+			description: "TestClassifyServerError",
+			err:         &core.ApiError{CodeReceived: 503},
+			expected:    ErrTransient,
+		},
+		{
+			description: "TestClassifyRejected",
+			err:         &core.ApiError{CodeReceived: 400, Message: "insufficient balance"},
+			expected:    ErrRejected,
+		},
+		{
+			description: "TestClassifyNoResponse",
+			err:         &core.ApiError{Message: "connection reset"},
+			expected:    ErrTransient,
+		},
+		{
+			description: "TestClassifyTimeout",
+			err:         fmt.Errorf("cannot call Exchange product err: %w", context.DeadlineExceeded),
+			expected:    ErrTransient,
+		},
+		{
+			description: "TestClassifyExchangeNotFound",
+			err:         &exchange.HttpError{StatusCode: 404, Message: "NotFound"},
+			expected:    ErrInvalidProduct,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			result := classify(tt.err)
+			if !errors.Is(result, tt.expected) {
+				t.Errorf("test: %s - expected: %v - received: %v", tt.description, tt.expected, result)
+			}
+		})
+	}
+}
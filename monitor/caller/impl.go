@@ -18,7 +18,9 @@ package caller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,15 +29,20 @@ import (
 	"go.uber.org/zap"
 
 	prime "github.com/coinbase-samples/prime-sdk-go"
-	"github.com/google/uuid"
 	"github.com/jellydator/ttlcache/v2"
 	"github.com/shopspring/decimal"
+	"golang.org/x/time/rate"
 )
 
 type apiCall struct {
 	config      *config.AppConfig
 	ordersCache *ttlcache.Cache
 	portfolioId string
+	retry       retryPolicy
+
+	// Limiters are shared by all calls to the same API
+	primeLimiter    *rate.Limiter
+	exchangeLimiter *rate.Limiter
 }
 
 func NewCaller(config *config.AppConfig) Caller {
@@ -48,9 +55,28 @@ func NewCaller(config *config.AppConfig) Caller {
 		config:      config,
 		ordersCache: ordersCache,
 		portfolioId: config.PrimeClient.Credentials.PortfolioId,
+		retry:       newRetryPolicy(config),
+
+		primeLimiter:    rate.NewLimiter(rate.Limit(config.PrimeRateLimit()), config.PrimeRateBurst()),
+		exchangeLimiter: rate.NewLimiter(rate.Limit(config.ExchangeRateLimit()), config.ExchangeRateBurst()),
 	}
 }
 
+// primeCall calls Prime with a new timeout for each attempt and retries
+// according to the retry policy. Each attempt waits for the Prime rate
+// limiter.
+func (ac apiCall) primeCall(name string, call func(ctx context.Context) error) error {
+	return ac.retry.do(name, func() error {
+		if err := ac.primeLimiter.Wait(context.Background()); err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), ac.config.PrimeCallTimeout())
+		defer cancel()
+		return call(ctx)
+	})
+}
+
 func (ac apiCall) PrimeDescribeTradingWallets() (WalletLookup, error) {
 
 	var cursor string
@@ -59,7 +85,7 @@ func (ac apiCall) PrimeDescribeTradingWallets() (WalletLookup, error) {
 
 	for {
 
-		w, nextCursor, err := ac.primeListTradingWallets(cursor)
+		w, nextCursor, err := ac.primeListWallets(prime.WalletTypeTrading, nil, cursor)
 
 		if err != nil {
 			return wallets, err
@@ -79,20 +105,48 @@ func (ac apiCall) PrimeDescribeTradingWallets() (WalletLookup, error) {
 	return wallets, nil
 }
 
-func (ac apiCall) primeListTradingWallets(cursor string) ([]*prime.Wallet, string, error) {
+func (ac apiCall) PrimeDescribeVaultWallets(symbols []string) ([]*prime.Wallet, error) {
+
+	var cursor string
 
-	ctx, cancel := context.WithTimeout(context.Background(), ac.config.PrimeCallTimeout())
-	defer cancel()
+	var wallets []*prime.Wallet
+
+	for {
+
+		w, nextCursor, err := ac.primeListWallets(prime.WalletTypeVault, symbols, cursor)
+
+		if err != nil {
+			return wallets, err
+		}
+
+		wallets = append(wallets, w...)
+
+		if len(nextCursor) == 0 {
+			break
+		}
+
+		cursor = nextCursor
+	}
+
+	return wallets, nil
+}
+
+func (ac apiCall) primeListWallets(walletType string, symbols []string, cursor string) ([]*prime.Wallet, string, error) {
 
 	request := &prime.ListWalletsRequest{
 		PortfolioId: ac.portfolioId,
-		Type:        prime.WalletTypeTrading,
+		Type:        walletType,
+		Symbols:     symbols,
 		Pagination: &prime.PaginationParams{
 			Cursor: cursor,
 		},
 	}
 
-	response, err := ac.config.PrimeClient.ListWallets(ctx, request)
+	var response *prime.ListWalletsResponse
+	err := ac.primeCall("ListWallets", func(ctx context.Context) (err error) {
+		response, err = ac.config.PrimeClient.ListWallets(ctx, request)
+		return
+	})
 	if err != nil {
 		return nil, "", err
 	}
@@ -130,15 +184,16 @@ func (ac apiCall) PrimeDescribeProducts() (ProductLookup, error) {
 
 func (ac apiCall) primeListProducts(cursor string) ([]*prime.Product, string, error) {
 
-	ctx, cancel := context.WithTimeout(context.Background(), ac.config.PrimeCallTimeout())
-	defer cancel()
-
 	request := &prime.ListProductsRequest{
 		PortfolioId: ac.portfolioId,
 		Pagination:  &prime.PaginationParams{Cursor: cursor},
 	}
 
-	response, err := ac.config.PrimeClient.ListProducts(ctx, request)
+	var response *prime.ListProductsResponse
+	err := ac.primeCall("ListProducts", func(ctx context.Context) (err error) {
+		response, err = ac.config.PrimeClient.ListProducts(ctx, request)
+		return
+	})
 	if err != nil {
 		return nil, "", err
 	}
@@ -166,34 +221,342 @@ func (ac apiCall) PrimeCalculateOrderSize(
 
 func (ac apiCall) PrimeDescribeTradingBalances() ([]*prime.Balance, error) {
 
-	ctx, cancel := context.WithTimeout(context.Background(), ac.config.PrimeCallTimeout())
-	defer cancel()
+	request := &prime.ListWalletBalancesRequest{
+		PortfolioId: ac.portfolioId,
+		Type:        prime.BalanceTypeTrading,
+	}
+
+	var response *prime.ListWalletBalancesResponse
+	err := ac.primeCall("ListWalletBalances", func(ctx context.Context) (err error) {
+		response, err = ac.config.PrimeClient.ListWalletBalances(ctx, request)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Balances, nil
+}
+
+func (ac apiCall) PrimeDescribeWalletBalance(walletId string) (*prime.Balance, error) {
+
+	request := &prime.GetWalletBalanceRequest{
+		PortfolioId: ac.portfolioId,
+		Id:          walletId,
+	}
+
+	var response *prime.GetWalletBalanceResponse
+	err := ac.primeCall("GetWalletBalance", func(ctx context.Context) (err error) {
+		response, err = ac.config.PrimeClient.GetWalletBalance(ctx, request)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to describe wallet balance - wallet id: %s %w", walletId, err)
+	}
+
+	return response.Balance, nil
+}
+
+// PrimeDescribeWalletTransactions returns the transactions of the wallet
+// created since start.
+func (ac apiCall) PrimeDescribeWalletTransactions(walletId string, start time.Time) ([]*prime.Transaction, error) {
+
+	var cursor string
+
+	var transactions []*prime.Transaction
+
+	for {
+
+		tx, nextCursor, err := ac.primeListWalletTransactions(walletId, start, cursor)
+
+		if err != nil {
+			return transactions, err
+		}
+
+		transactions = append(transactions, tx...)
+
+		if len(nextCursor) == 0 {
+			break
+		}
+
+		cursor = nextCursor
+	}
+
+	return transactions, nil
+}
+
+func (ac apiCall) primeListWalletTransactions(walletId string, start time.Time, cursor string) ([]*prime.Transaction, string, error) {
 
-	response, err := ac.config.PrimeClient.ListWalletBalances(
-		ctx,
-		&prime.ListWalletBalancesRequest{
+	request := &prime.ListWalletTransactionsRequest{
+		PortfolioId: ac.portfolioId,
+		WalletId:    walletId,
+		Start:       start,
+		Pagination: &prime.PaginationParams{
+			Cursor: cursor,
+		},
+	}
+
+	var response *prime.ListWalletTransactionsResponse
+	err := ac.primeCall("ListWalletTransactions", func(ctx context.Context) (err error) {
+		response, err = ac.config.PrimeClient.ListWalletTransactions(ctx, request)
+		return
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to list wallet transactions - wallet id: %s %w", walletId, err)
+	}
+
+	return response.Transactions, response.Pagination.NextCursor, nil
+}
+
+func (ac apiCall) PrimeDescribeOrder(orderId string) (*prime.Order, error) {
+
+	request := &prime.GetOrderRequest{
+		PortfolioId: ac.portfolioId,
+		OrderId:     orderId,
+	}
+
+	var response *prime.GetOrderResponse
+	err := ac.primeCall("GetOrder", func(ctx context.Context) (err error) {
+		response, err = ac.config.PrimeClient.GetOrder(ctx, request)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to describe order - order id: %s %w", orderId, err)
+	}
+
+	return response.Order, nil
+}
+
+func (ac apiCall) PrimeDescribeOrderFills(orderId string) ([]*prime.OrderFill, error) {
+
+	var fills []*prime.OrderFill
+
+	var cursor string
+
+	for {
+
+		request := &prime.ListOrderFillsRequest{
 			PortfolioId: ac.portfolioId,
-			Type:        prime.BalanceTypeTrading,
+			OrderId:     orderId,
+			Pagination:  &prime.PaginationParams{Cursor: cursor},
+		}
+
+		var response *prime.ListOrderFillsResponse
+		err := ac.primeCall("ListOrderFills", func(ctx context.Context) (err error) {
+			response, err = ac.config.PrimeClient.ListOrderFills(ctx, request)
+			return
+		})
+		if err != nil {
+			return fills, fmt.Errorf("unable to describe order fills - order id: %s %w", orderId, err)
+		}
+
+		fills = append(fills, response.Fills...)
+
+		if response.Pagination == nil || len(response.Pagination.NextCursor) == 0 {
+			break
+		}
+
+		cursor = response.Pagination.NextCursor
+	}
+
+	return fills, nil
+}
+
+// PrimeDescribeOpenOrders returns the open orders in the portfolio. If
+// the product id is empty, orders for all products are returned. Prime
+// does not return more than 1k open orders.
+func (ac apiCall) PrimeDescribeOpenOrders(productId string) ([]*prime.Order, error) {
+
+	request := &prime.ListOpenOrdersRequest{
+		PortfolioId: ac.portfolioId,
+		ProductId:   productId,
+	}
+
+	var response *prime.ListOpenOrdersResponse
+	err := ac.primeCall("ListOpenOrders", func(ctx context.Context) (err error) {
+		response, err = ac.config.PrimeClient.ListOpenOrders(ctx, request)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to describe open orders - product id: %s %w", productId, err)
+	}
+
+	return response.Orders, nil
+}
+
+// PrimeDescribeOrders returns the orders of the product with the status
+// created since start. Open orders are not returned.
+func (ac apiCall) PrimeDescribeOrders(status, productId string, start time.Time) ([]*prime.Order, error) {
+
+	var cursor string
+
+	var orders []*prime.Order
+
+	for {
+
+		o, nextCursor, err := ac.primeListOrders(status, productId, start, cursor)
+
+		if err != nil {
+			return orders, err
+		}
+
+		orders = append(orders, o...)
+
+		if len(nextCursor) == 0 {
+			break
+		}
+
+		cursor = nextCursor
+	}
+
+	return orders, nil
+}
+
+func (ac apiCall) primeListOrders(status, productId string, start time.Time, cursor string) ([]*prime.Order, string, error) {
+
+	request := &prime.ListOrdersRequest{
+		PortfolioId: ac.portfolioId,
+		Statuses:    []string{status},
+		ProductIds:  []string{productId},
+		Start:       start,
+		Pagination: &prime.PaginationParams{
+			Cursor: cursor,
 		},
-	)
+	}
 
+	var response *prime.ListOrdersResponse
+	err := ac.primeCall("ListOrders", func(ctx context.Context) (err error) {
+		response, err = ac.config.PrimeClient.ListOrders(ctx, request)
+		return
+	})
 	if err != nil {
-		return nil, err
+		return nil, "", fmt.Errorf("unable to list orders - status: %s - product id: %s %w", status, productId, err)
 	}
 
-	return response.Balances, nil
+	return response.Orders, response.Pagination.NextCursor, nil
+}
+
+func (ac apiCall) PrimeCancelOrder(orderId string) error {
+
+	request := &prime.CancelOrderRequest{
+		PortfolioId: ac.portfolioId,
+		OrderId:     orderId,
+	}
+
+	err := ac.primeCall("CancelOrder", func(ctx context.Context) (err error) {
+		_, err = ac.config.PrimeClient.CancelOrder(ctx, request)
+		return
+	})
+	if err != nil {
+		return fmt.Errorf("unable to cancel order - order id: %s %w", orderId, err)
+	}
+
+	return nil
+}
+
+// PrimeDescribePendingTransactionActivities returns the transaction
+// activities for the symbols created since start that are still
+// processing, including those waiting for approval.
+func (ac apiCall) PrimeDescribePendingTransactionActivities(symbols []string, start time.Time) ([]*prime.Activity, error) {
+
+	var cursor string
+
+	var activities []*prime.Activity
+
+	for {
+
+		a, nextCursor, err := ac.primeListPendingTransactionActivities(symbols, start, cursor)
+
+		if err != nil {
+			return activities, err
+		}
+
+		activities = append(activities, a...)
+
+		if len(nextCursor) == 0 {
+			break
+		}
+
+		cursor = nextCursor
+	}
+
+	return activities, nil
+}
+
+func (ac apiCall) primeListPendingTransactionActivities(symbols []string, start time.Time, cursor string) ([]*prime.Activity, string, error) {
+
+	request := &prime.ListActivitiesRequest{
+		PortfolioId: ac.portfolioId,
+		Symbols:     symbols,
+		Categories:  []string{activityCategoryTransaction},
+		Statuses:    []string{activityStatusProcessing},
+		Start:       start,
+		Pagination: &prime.PaginationParams{
+			Cursor: cursor,
+		},
+	}
+
+	var response *prime.ListActivitiesResponse
+	err := ac.primeCall("ListActivities", func(ctx context.Context) (err error) {
+		response, err = ac.config.PrimeClient.ListActivities(ctx, request)
+		return
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to list pending activities %w", err)
+	}
+
+	return response.Activities, response.Pagination.NextCursor, nil
+}
+
+func (ac apiCall) PrimeDescribeActivity(activityId string) (*prime.Activity, error) {
+
+	request := &prime.GetActivityRequest{
+		PortfolioId: ac.portfolioId,
+		Id:          activityId,
+	}
+
+	var response *prime.GetActivityResponse
+	err := ac.primeCall("GetActivity", func(ctx context.Context) (err error) {
+		response, err = ac.config.PrimeClient.GetActivity(ctx, request)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to describe activity - activity id: %s %w", activityId, err)
+	}
+
+	return response.Activity, nil
+}
+
+func (ac apiCall) PrimeDescribeTransaction(transactionId string) (*prime.Transaction, error) {
+
+	request := &prime.GetTransactionRequest{
+		PortfolioId:   ac.portfolioId,
+		TransactionId: transactionId,
+	}
+
+	var response *prime.GetTransactionResponse
+	err := ac.primeCall("GetTransaction", func(ctx context.Context) (err error) {
+		response, err = ac.config.PrimeClient.GetTransaction(ctx, request)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to describe transaction - transaction id: %s %w", transactionId, err)
+	}
+
+	return response.Transaction, nil
 }
 
 func (ac apiCall) PrimeCreateConversion(
 	sourceWallet,
 	destinationWallet *prime.Wallet,
 	amount decimal.Decimal,
-) error {
+	digits int32,
+) (*prime.CreateConversionResponse, error) {
 
-	round := amount.RoundFloor(ac.config.StablecoinFiatDigits)
+	round := amount.RoundFloor(digits)
 
 	if round.IsZero() {
-		return nil
+		return nil, ErrZeroConversionAmount
 	}
 
 	zap.L().Info(
@@ -203,8 +566,35 @@ func (ac apiCall) PrimeCreateConversion(
 		zap.Any("amount", round),
 	)
 
-	ctx, cancel := context.WithTimeout(context.Background(), ac.config.PrimeCallTimeout())
-	defer cancel()
+	// Conversions of the same amount between the same wallets are deduped
+	// for the conversion window. If the outcome of the previous attempt is
+	// unknown, its idempotency key is reused so Prime does not convert the
+	// amount twice.
+	dedupeKey := generateUniqueId(
+		conversionCachePrefix,
+		sourceWallet.Id,
+		destinationWallet.Id,
+		round.String(),
+	)
+
+	var idempotencyKey string
+
+	if cached, err := ac.ordersCache.Get(dedupeKey); err == nil {
+		attempt := cached.(conversionAttempt)
+		if len(attempt.activityId) > 0 {
+			return nil, &DuplicateConversionError{IdempotencyKey: attempt.idempotencyKey, ActivityId: attempt.activityId}
+		}
+		idempotencyKey = attempt.idempotencyKey
+	} else {
+		idempotencyKey = generateIdempotencyKey(
+			sourceWallet.Id,
+			destinationWallet.Id,
+			round.String(),
+			strconv.FormatInt(time.Now().Truncate(conversionWindow).Unix(), 10),
+		)
+	}
+
+	ac.ordersCache.SetWithTTL(dedupeKey, conversionAttempt{idempotencyKey: idempotencyKey}, conversionWindow)
 
 	request := &prime.CreateConversionRequest{
 		PortfolioId:         ac.portfolioId,
@@ -213,14 +603,35 @@ func (ac apiCall) PrimeCreateConversion(
 		SourceSymbol:        strings.ToUpper(sourceWallet.Symbol),
 		DestinationSymbol:   strings.ToUpper(destinationWallet.Symbol),
 		Amount:              round.String(),
-		IdempotencyKey:      uuid.New().String(),
+		IdempotencyKey:      idempotencyKey,
 	}
 
-	response, err := ac.config.PrimeClient.CreateConversion(ctx, request)
+	// Retries reuse the request and its idempotency key
+	var response *prime.CreateConversionResponse
+	err := ac.primeCall("CreateConversion", func(ctx context.Context) (err error) {
+		response, err = ac.config.PrimeClient.CreateConversion(ctx, request)
+		return
+	})
+	if errors.Is(err, ErrRejected) {
+		// Prime did not accept the conversion, so it can be submitted again
+		ac.ordersCache.Remove(dedupeKey)
+	}
 	if err != nil {
-		return err
+		return nil, fmt.Errorf(
+			"unable to create conversion - idempotency key: %s - symbol: %s - amount: %v - %w",
+			idempotencyKey,
+			sourceWallet.Symbol,
+			round,
+			err,
+		)
 	}
 
+	ac.ordersCache.SetWithTTL(
+		dedupeKey,
+		conversionAttempt{idempotencyKey: idempotencyKey, activityId: response.ActivityId},
+		conversionWindow,
+	)
+
 	zap.L().Info(
 		"fiat conversion submitted",
 		zap.String("sourceSymbol", sourceWallet.Symbol),
@@ -229,7 +640,127 @@ func (ac apiCall) PrimeCreateConversion(
 		zap.String("activityId", response.ActivityId),
 	)
 
-	return nil
+	return response, nil
+}
+
+// PrimeCreateWalletTransfer transfers the amount between two wallets in
+// the portfolio. The idempotency key is derived from the wallets, the
+// amount, and the time bucket, so a transfer with an unknown outcome is
+// not submitted twice when it is retried within the window. Transfers
+// out of a vault wallet may require approval in Prime before they are
+// processed.
+func (ac apiCall) PrimeCreateWalletTransfer(
+	sourceWallet,
+	destinationWallet *prime.Wallet,
+	amount decimal.Decimal,
+) (*prime.CreateWalletTransferResponse, error) {
+
+	request := &prime.CreateWalletTransferRequest{
+		PortfolioId:         ac.portfolioId,
+		SourceWalletId:      sourceWallet.Id,
+		Symbol:              strings.ToUpper(sourceWallet.Symbol),
+		DestinationWalletId: destinationWallet.Id,
+		Amount:              amount.String(),
+		IdempotencyKey: generateIdempotencyKey(
+			transferKeyPrefix,
+			sourceWallet.Id,
+			destinationWallet.Id,
+			amount.String(),
+			strconv.FormatInt(time.Now().Truncate(transferWindow).Unix(), 10),
+		),
+	}
+
+	var response *prime.CreateWalletTransferResponse
+	err := ac.primeCall("CreateWalletTransfer", func(ctx context.Context) (err error) {
+		response, err = ac.config.PrimeClient.CreateWalletTransfer(ctx, request)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf(
+			"unable to create wallet transfer - idempotency key: %s - symbol: %s - amount: %v - %w",
+			request.IdempotencyKey,
+			sourceWallet.Symbol,
+			amount,
+			err,
+		)
+	}
+
+	zap.L().Info(
+		"wallet transfer submitted",
+		zap.String("symbol", sourceWallet.Symbol),
+		zap.String("sourceWalletId", sourceWallet.Id),
+		zap.String("destinationWalletId", destinationWallet.Id),
+		zap.Any("amount", amount),
+		zap.String("activityId", response.ActivityId),
+		zap.String("approvalUrl", response.ApprovalUrl),
+	)
+
+	return response, nil
+}
+
+// PrimeCreateWalletWithdrawal withdraws the amount to the payment method
+// if it is set, otherwise to the blockchain address. The destination must
+// be whitelisted in Prime. The idempotency key is derived in the same way
+// as for wallet transfers.
+func (ac apiCall) PrimeCreateWalletWithdrawal(
+	sourceWallet *prime.Wallet,
+	amount decimal.Decimal,
+	paymentMethodId,
+	address string,
+) (*prime.CreateWalletWithdrawalResponse, error) {
+
+	request := &prime.CreateWalletWithdrawalRequest{
+		PortfolioId:    ac.portfolioId,
+		SourceWalletId: sourceWallet.Id,
+		Symbol:         strings.ToUpper(sourceWallet.Symbol),
+		Amount:         amount.String(),
+	}
+
+	destination := address
+
+	if len(paymentMethodId) > 0 {
+		destination = paymentMethodId
+		request.DestinationType = destinationTypePaymentMethod
+		request.PaymentMethod = &prime.CreateWalletWithdrawalPaymentMethod{Id: paymentMethodId}
+	} else {
+		request.DestinationType = destinationTypeBlockchain
+		request.BlockchainAddress = &prime.BlockchainAddress{Address: address}
+	}
+
+	request.IdempotencyKey = generateIdempotencyKey(
+		withdrawalKeyPrefix,
+		sourceWallet.Id,
+		destination,
+		amount.String(),
+		strconv.FormatInt(time.Now().Truncate(transferWindow).Unix(), 10),
+	)
+
+	var response *prime.CreateWalletWithdrawalResponse
+	err := ac.primeCall("CreateWalletWithdrawal", func(ctx context.Context) (err error) {
+		response, err = ac.config.PrimeClient.CreateWalletWithdrawal(ctx, request)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf(
+			"unable to create wallet withdrawal - idempotency key: %s - symbol: %s - amount: %v - %w",
+			request.IdempotencyKey,
+			sourceWallet.Symbol,
+			amount,
+			err,
+		)
+	}
+
+	zap.L().Info(
+		"wallet withdrawal submitted",
+		zap.String("symbol", sourceWallet.Symbol),
+		zap.String("sourceWalletId", sourceWallet.Id),
+		zap.String("destination", destination),
+		zap.Any("amount", amount),
+		zap.String("activityId", response.ActivityId),
+		zap.String("approvalUrl", response.ApprovalUrl),
+	)
+
+	return response, nil
 }
 
 func (ac apiCall) PrimeCreateMarketOrder(
@@ -237,13 +768,13 @@ func (ac apiCall) PrimeCreateMarketOrder(
 	value,
 	orderSize decimal.Decimal,
 	asset *prime.Balance,
-) error {
+) (*prime.CreateOrderResponse, error) {
 	holds, err := asset.HoldsNum()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	clientOrderId := generateUniqueId(
+	clientOrderId := generateClientOrderId(
 		productId,
 		prime.OrderSideSell,
 		prime.OrderTypeMarket,
@@ -253,7 +784,7 @@ func (ac apiCall) PrimeCreateMarketOrder(
 	)
 
 	if _, exists := ac.ordersCache.Get(clientOrderId); exists == nil {
-		return nil
+		return nil, &DuplicateOrderError{ClientOrderId: clientOrderId}
 	}
 
 	zap.L().Info(
@@ -272,12 +803,14 @@ func (ac apiCall) PrimeCreateMarketOrder(
 		clientOrderId,
 	)
 
-	ctx, cancel := context.WithTimeout(context.Background(), ac.config.PrimeCallTimeout())
-	defer cancel()
-
-	response, err := ac.config.PrimeClient.CreateOrder(ctx, request)
+	// Retries reuse the request and its client order id
+	var response *prime.CreateOrderResponse
+	err = ac.primeCall("CreateOrder", func(ctx context.Context) (err error) {
+		response, err = ac.config.PrimeClient.CreateOrder(ctx, request)
+		return
+	})
 	if err != nil {
-		return fmt.Errorf(
+		return nil, fmt.Errorf(
 			"unable to create market order - client order id: %s - symbol: %s - size: %v %w",
 			clientOrderId,
 			asset.Symbol,
@@ -294,7 +827,7 @@ func (ac apiCall) PrimeCreateMarketOrder(
 		zap.String("clientOrderId", clientOrderId),
 	)
 
-	return nil
+	return response, nil
 }
 
 func (ac apiCall) createMarketOrderRequest(
@@ -322,15 +855,16 @@ func (ac apiCall) PrimeCreateTwapOrder(
 	value,
 	orderSize,
 	limitPrice decimal.Decimal,
+	duration time.Duration,
 	asset *prime.Balance,
-) error {
+) (*prime.CreateOrderResponse, error) {
 
 	holds, err := asset.HoldsNum()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	clientOrderId := generateUniqueId(
+	clientOrderId := generateClientOrderId(
 		productId,
 		prime.OrderSideSell,
 		prime.OrderTypeTwap,
@@ -340,7 +874,7 @@ func (ac apiCall) PrimeCreateTwapOrder(
 	)
 
 	if _, exists := ac.ordersCache.Get(clientOrderId); exists == nil {
-		return nil
+		return nil, &DuplicateOrderError{ClientOrderId: clientOrderId}
 	}
 
 	zap.L().Info(
@@ -357,16 +891,18 @@ func (ac apiCall) PrimeCreateTwapOrder(
 		orderSize,
 		asset,
 		limitPrice,
-		ac.config.TwapDuration(),
+		duration,
 		clientOrderId,
 	)
 
-	ctx, cancel := context.WithTimeout(context.Background(), ac.config.PrimeCallTimeout())
-	defer cancel()
-
-	response, err := ac.config.PrimeClient.CreateOrder(ctx, request)
+	// Retries reuse the request and its client order id
+	var response *prime.CreateOrderResponse
+	err = ac.primeCall("CreateOrder", func(ctx context.Context) (err error) {
+		response, err = ac.config.PrimeClient.CreateOrder(ctx, request)
+		return
+	})
 	if err != nil {
-		return fmt.Errorf(
+		return nil, fmt.Errorf(
 			"unable to create twap order - client order id: %s - symbol: %s - size: %v %w",
 			clientOrderId,
 			asset.Symbol,
@@ -383,7 +919,7 @@ func (ac apiCall) PrimeCreateTwapOrder(
 		zap.String("clientOrderId", clientOrderId),
 	)
 
-	return nil
+	return response, nil
 }
 
 func (ac apiCall) createTwapOrderRequest(
@@ -416,5 +952,13 @@ func (ac apiCall) createTwapOrderRequest(
 }
 
 func (ac apiCall) ExchangeCurrentProductPrice(productId string) (decimal.Decimal, error) {
-	return exchange.CurrentProductPrice(productId, ac.config.PrimeCallTimeout(), ac.config.HttpClient)
+	var price decimal.Decimal
+	err := ac.retry.do("CurrentProductPrice", func() (err error) {
+		if err = ac.exchangeLimiter.Wait(context.Background()); err != nil {
+			return
+		}
+		price, err = exchange.CurrentProductPrice(productId, ac.config.PrimeCallTimeout(), ac.config.HttpClient)
+		return
+	})
+	return price, err
 }
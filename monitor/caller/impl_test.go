@@ -0,0 +1,64 @@
+/**
+ * Copyright 2023-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package caller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/config"
+	"golang.org/x/time/rate"
+)
+
+func TestPrimeCallRateLimit(t *testing.T) {
+
+	cases := []struct {
+		description string
+		limit       rate.Limit
+		burst       int
+		calls       int
+		minElapsed  time.Duration
+	}{
+		{description: "TestPrimeCallRateLimitPaced", limit: 20, burst: 1, calls: 5, minElapsed: 4 * 50 * time.Millisecond},
+		{description: "TestPrimeCallRateLimitBurst", limit: 20, burst: 3, calls: 5, minElapsed: 2 * 50 * time.Millisecond},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			ac := apiCall{
+				config:       &config.AppConfig{PrimeCallTimeoutInSeconds: "5"},
+				retry:        retryPolicy{maxAttempts: 1},
+				primeLimiter: rate.NewLimiter(tt.limit, tt.burst),
+			}
+
+			start := time.Now()
+
+			for i := 0; i < tt.calls; i++ {
+				if err := ac.primeCall("Test", func(ctx context.Context) error { return nil }); err != nil {
+					t.Fatalf("test: %s - unexpected error: %v", tt.description, err)
+				}
+			}
+
+			// Allow for the limiter refilling slightly early
+			if elapsed := time.Since(start); elapsed < tt.minElapsed-10*time.Millisecond {
+				t.Errorf("test: %s - expected at least: %s - received: %s", tt.description, tt.minElapsed, elapsed)
+			}
+		})
+	}
+}
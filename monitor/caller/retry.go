@@ -0,0 +1,118 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package caller
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/config"
+	"go.uber.org/zap"
+)
+
+// retryPolicy retries failed calls with exponential backoff and jitter.
+// Calls are retried if the response status code is retryable or if no
+// response was received because of a transient failure.
+type retryPolicy struct {
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	jitter         float64
+	statusCodes    map[int]bool
+}
+
+func newRetryPolicy(config *config.AppConfig) retryPolicy {
+
+	p := retryPolicy{
+		maxAttempts:    config.RetryMaxAttempts(),
+		initialBackoff: config.RetryInitialBackoff(),
+		maxBackoff:     config.RetryMaxBackoff(),
+		jitter:         float64(config.RetryJitterPercent()) / 100,
+		statusCodes:    make(map[int]bool),
+	}
+
+	for _, c := range config.RetryStatusCodes() {
+		p.statusCodes[c] = true
+	}
+
+	return p
+}
+
+// do calls the function until it succeeds, the error is not retryable,
+// or the maximum number of attempts is reached. The returned error is
+// classified.
+func (p retryPolicy) do(name string, call func() error) (err error) {
+
+	for attempt := 1; ; attempt++ {
+
+		if err = classify(call()); err == nil {
+			return
+		}
+
+		if attempt >= p.maxAttempts || !p.retryable(err) {
+			return
+		}
+
+		backoff := p.backoff(attempt)
+
+		zap.L().Warn(
+			"retrying call",
+			zap.String("call", name),
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+
+		time.Sleep(backoff)
+	}
+}
+
+func (p retryPolicy) retryable(err error) bool {
+
+	var callErr *CallError
+	if !errors.As(err, &callErr) {
+		return false
+	}
+
+	if callErr.StatusCode == 0 {
+		return errors.Is(err, ErrTransient)
+	}
+
+	return p.statusCodes[callErr.StatusCode]
+}
+
+// backoff returns the delay before the next attempt. The delay doubles
+// on each attempt up to the max and is randomly adjusted by up to the
+// jitter percent in either direction.
+func (p retryPolicy) backoff(attempt int) time.Duration {
+
+	backoff := p.initialBackoff
+	for i := 1; i < attempt && backoff < p.maxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > p.maxBackoff {
+		backoff = p.maxBackoff
+	}
+
+	if p.jitter > 0 {
+		backoff += time.Duration((rand.Float64()*2 - 1) * p.jitter * float64(backoff))
+	}
+
+	return backoff
+}
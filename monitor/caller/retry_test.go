@@ -0,0 +1,84 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package caller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/core-go"
+)
+
+func TestRetryPolicyDo(t *testing.T) {
+
+	p := retryPolicy{
+		maxAttempts:    3,
+		initialBackoff: time.Millisecond,
+		maxBackoff:     time.Millisecond,
+		statusCodes:    map[int]bool{429: true, 503: true},
+	}
+
+	cases := []struct {
+		description string
+		err         error
+		attempts    int
+	}{
+		{
+			description: "TestRetryPolicyDoRetryableStatus",
+			err:         &core.ApiError{CodeReceived: 503},
+			attempts:    3,
+		},
+		{
+			description: "TestRetryPolicyDoNoResponse",
+			err:         &core.ApiError{Message: "connection reset"},
+			attempts:    3,
+		},
+		{
+			description: "TestRetryPolicyDoAuth",
+			err:         &core.ApiError{CodeReceived: 401},
+			attempts:    1,
+		},
+		{
+			description: "TestRetryPolicyDoRejected",
+			err:         &core.ApiError{CodeReceived: 400},
+			attempts:    1,
+		},
+		{
+			description: "TestRetryPolicyDoSuccess",
+			attempts:    1,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			var attempts int
+			err := p.do(tt.description, func() error {
+				attempts++
+				return tt.err
+			})
+
+			if attempts != tt.attempts {
+				t.Errorf("test: %s - expected attempts: %d - received: %d", tt.description, tt.attempts, attempts)
+			}
+
+			if (tt.err == nil) != (err == nil) {
+				t.Errorf("test: %s - unexpected error: %v", tt.description, err)
+			}
+		})
+	}
+}
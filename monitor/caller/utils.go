@@ -20,14 +20,64 @@ import (
 	"crypto/md5"
 	"fmt"
 	"strings"
+	"time"
 
 	prime "github.com/coinbase-samples/prime-sdk-go"
+	"github.com/google/uuid"
+)
+
+const (
+	// conversionWindow is how long a conversion is deduped and the size
+	// of the time bucket in its idempotency key
+	conversionWindow      = 10 * time.Minute
+	conversionCachePrefix = "conversion"
+
+	// transferWindow is the size of the time bucket in the idempotency key
+	// of a wallet transfer
+	transferWindow    = 10 * time.Minute
+	transferKeyPrefix = "transfer"
+
+	withdrawalKeyPrefix          = "withdrawal"
+	destinationTypePaymentMethod = "DESTINATION_PAYMENT_METHOD"
+	destinationTypeBlockchain    = "DESTINATION_BLOCKCHAIN"
+
+	// clientOrderIdPrefix marks the orders submitted by the liquidator
+	clientOrderIdPrefix = "liquidator-"
+
+	activityCategoryTransaction = "ACTIVITY_CATEGORY_TRANSACTION"
+	activityStatusProcessing    = "ACTIVITY_STATUS_PROCESSING"
 )
 
 func generateUniqueId(params ...string) string {
 	return fmt.Sprintf("%x", md5.Sum([]byte(strings.Join(params, "-"))))
 }
 
+// generateClientOrderId returns the client order id of a liquidator
+// order. The same parameters always produce the same id, so an order is
+// not submitted twice.
+func generateClientOrderId(params ...string) string {
+	return clientOrderIdPrefix + generateUniqueId(params...)
+}
+
+// IsLiquidatorOrder returns true if the client order id was generated by
+// the liquidator.
+func IsLiquidatorOrder(clientOrderId string) bool {
+	return strings.HasPrefix(clientOrderId, clientOrderIdPrefix)
+}
+
+// generateIdempotencyKey returns a name based UUID so that the same
+// parameters always produce the same key.
+func generateIdempotencyKey(params ...string) string {
+	return uuid.NewMD5(uuid.Nil, []byte(strings.Join(params, "-"))).String()
+}
+
+// conversionAttempt is cached for each conversion request. The activity
+// id is set once Prime accepts the conversion.
+type conversionAttempt struct {
+	idempotencyKey string
+	activityId     string
+}
+
 type ProductLookup map[string]*prime.Product
 
 func (pl ProductLookup) Lookup(id string) *prime.Product {
@@ -55,16 +105,3 @@ func (wl WalletLookup) Lookup(id string) *prime.Wallet {
 func (wl WalletLookup) Add(w *prime.Wallet) {
 	wl[w.Symbol] = w
 }
-
-type ConvertSymbols map[string]bool
-
-func (cs ConvertSymbols) Is(v string) (c bool) {
-	if _, found := cs[v]; found {
-		c = true
-	}
-	return
-}
-
-func (cs ConvertSymbols) Add(v string) {
-	cs[v] = true
-}
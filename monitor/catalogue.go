@@ -0,0 +1,170 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/monitor/caller"
+	prime "github.com/coinbase-samples/prime-sdk-go"
+	"go.uber.org/zap"
+)
+
+// catalogueMissRefreshInterval is the minimum time between refreshes
+// caused by a lookup miss, so an unknown symbol or product does not
+// trigger a refresh on every pass.
+const catalogueMissRefreshInterval = time.Minute
+
+// catalogue caches the trading wallets and products, which rarely
+// change, and refreshes each of them once they are older than their
+// TTL. A lookup miss also refreshes the cache so new wallets and
+// products are found before the TTL expires. It is safe for concurrent
+// use.
+type catalogue struct {
+	call              caller.Caller
+	walletsTtl        time.Duration
+	productsTtl       time.Duration
+	wallets           caller.WalletLookup
+	products          caller.ProductLookup
+	walletsRefreshed  time.Time
+	productsRefreshed time.Time
+	lock              sync.RWMutex
+	refreshLock       sync.Mutex
+}
+
+func newCatalogue(call caller.Caller, walletsTtl, productsTtl time.Duration) *catalogue {
+	return &catalogue{
+		call:        call,
+		walletsTtl:  walletsTtl,
+		productsTtl: productsTtl,
+	}
+}
+
+// refresh reloads the wallets and products that are older than their
+// TTL.
+func (c *catalogue) refresh(now time.Time) error {
+
+	if err := c.refreshWallets(now, c.walletsTtl); err != nil {
+		return err
+	}
+
+	return c.refreshProducts(now, c.productsTtl)
+}
+
+// wallet returns the trading wallet for the symbol or nil if the
+// symbol does not have a trading wallet.
+func (c *catalogue) wallet(symbol string) (*prime.Wallet, error) {
+
+	if w := c.lookupWallet(symbol); w != nil {
+		return w, nil
+	}
+
+	zap.L().Debug("wallet not cached", zap.String("symbol", symbol))
+
+	if err := c.refreshWallets(time.Now(), catalogueMissRefreshInterval); err != nil {
+		return nil, err
+	}
+
+	return c.lookupWallet(symbol), nil
+}
+
+// product returns the product or nil if the product does not exist.
+func (c *catalogue) product(productId string) (*prime.Product, error) {
+
+	if p := c.lookupProduct(productId); p != nil {
+		return p, nil
+	}
+
+	zap.L().Debug("product not cached", zap.String("productId", productId))
+
+	if err := c.refreshProducts(time.Now(), catalogueMissRefreshInterval); err != nil {
+		return nil, err
+	}
+
+	return c.lookupProduct(productId), nil
+}
+
+func (c *catalogue) lookupWallet(symbol string) *prime.Wallet {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.wallets.Lookup(symbol)
+}
+
+func (c *catalogue) lookupProduct(productId string) *prime.Product {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.products.Lookup(productId)
+}
+
+// refreshWallets reloads the wallets if they are older than the max
+// age. Refreshes are serialized, so concurrent misses result in a
+// single reload.
+func (c *catalogue) refreshWallets(now time.Time, maxAge time.Duration) error {
+
+	c.refreshLock.Lock()
+	defer c.refreshLock.Unlock()
+
+	if fresh(c.walletsRefreshed, now, maxAge) {
+		return nil
+	}
+
+	wallets, err := c.call.PrimeDescribeTradingWallets()
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.wallets = wallets
+	c.walletsRefreshed = now
+
+	return nil
+}
+
+// refreshProducts reloads the products if they are older than the max
+// age.
+func (c *catalogue) refreshProducts(now time.Time, maxAge time.Duration) error {
+
+	c.refreshLock.Lock()
+	defer c.refreshLock.Unlock()
+
+	if fresh(c.productsRefreshed, now, maxAge) {
+		return nil
+	}
+
+	products, err := c.call.PrimeDescribeProducts()
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.products = products
+	c.productsRefreshed = now
+
+	return nil
+}
+
+// fresh returns true if the refresh time is within the max age. The
+// refresh times are only read and written while holding the refresh
+// lock.
+func fresh(refreshed, now time.Time, maxAge time.Duration) bool {
+	return !refreshed.IsZero() && now.Sub(refreshed) < maxAge
+}
@@ -0,0 +1,124 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/monitor/caller"
+	prime "github.com/coinbase-samples/prime-sdk-go"
+)
+
+// catalogueCaller returns the configured wallets and products and
+// counts the calls. Other caller methods are not implemented.
+type catalogueCaller struct {
+	caller.Caller
+	wallets       caller.WalletLookup
+	products      caller.ProductLookup
+	walletCalls   int
+	productsCalls int
+}
+
+func (c *catalogueCaller) PrimeDescribeTradingWallets() (caller.WalletLookup, error) {
+	c.walletCalls++
+	return c.wallets, nil
+}
+
+func (c *catalogueCaller) PrimeDescribeProducts() (caller.ProductLookup, error) {
+	c.productsCalls++
+	return c.products, nil
+}
+
+func TestCatalogueRefresh(t *testing.T) {
+
+	call := &catalogueCaller{
+		wallets:  caller.WalletLookup{"USD": &prime.Wallet{Symbol: "USD"}},
+		products: caller.ProductLookup{"BTC-USD": &prime.Product{Id: "BTC-USD"}},
+	}
+
+	c := newCatalogue(call, 10*time.Minute, time.Hour)
+
+	now := time.Now()
+
+	if err := c.refresh(now); err != nil {
+		t.Fatalf("cannot refresh catalogue: %v", err)
+	}
+
+	if err := c.refresh(now.Add(5 * time.Minute)); err != nil {
+		t.Fatalf("cannot refresh catalogue: %v", err)
+	}
+
+	if call.walletCalls != 1 || call.productsCalls != 1 {
+		t.Errorf("expected one call each - received wallets: %d - products: %d", call.walletCalls, call.productsCalls)
+	}
+
+	if err := c.refresh(now.Add(15 * time.Minute)); err != nil {
+		t.Fatalf("cannot refresh catalogue: %v", err)
+	}
+
+	if call.walletCalls != 2 || call.productsCalls != 1 {
+		t.Errorf("expected wallets only to refresh - received wallets: %d - products: %d", call.walletCalls, call.productsCalls)
+	}
+}
+
+func TestCatalogueRefreshOnMiss(t *testing.T) {
+
+	call := &catalogueCaller{
+		wallets:  caller.WalletLookup{"USD": &prime.Wallet{Symbol: "USD"}},
+		products: caller.ProductLookup{"BTC-USD": &prime.Product{Id: "BTC-USD"}},
+	}
+
+	c := newCatalogue(call, time.Hour, time.Hour)
+
+	// Refresh as if the cache was loaded before the miss refresh interval
+	if err := c.refresh(time.Now().Add(-2 * catalogueMissRefreshInterval)); err != nil {
+		t.Fatalf("cannot refresh catalogue: %v", err)
+	}
+
+	call.products = caller.ProductLookup{
+		"BTC-USD": &prime.Product{Id: "BTC-USD"},
+		"ETH-USD": &prime.Product{Id: "ETH-USD"},
+	}
+
+	p, err := c.product("ETH-USD")
+	if err != nil {
+		t.Fatalf("cannot look up product: %v", err)
+	}
+
+	if p == nil || call.productsCalls != 2 {
+		t.Errorf("expected product after refresh - received: %v - calls: %d", p, call.productsCalls)
+	}
+
+	// The products were just refreshed, so an unknown product does not
+	// refresh them again
+	if p, err = c.product("SOL-USD"); err != nil || p != nil {
+		t.Errorf("expected unknown product - received: %v - err: %v", p, err)
+	}
+
+	if call.productsCalls != 2 {
+		t.Errorf("expected no refresh - received calls: %d", call.productsCalls)
+	}
+
+	if w, err := c.wallet("usd"); err != nil || w == nil {
+		t.Errorf("expected wallet - received: %v - err: %v", w, err)
+	}
+
+	if call.walletCalls != 1 {
+		t.Errorf("expected no wallet refresh - received calls: %d", call.walletCalls)
+	}
+}
@@ -0,0 +1,320 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/audit"
+	"github.com/coinbase-samples/prime-liquidator-go/config"
+	"github.com/coinbase-samples/prime-liquidator-go/monitor/caller"
+	prime "github.com/coinbase-samples/prime-sdk-go"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// ErrNotLeader is returned by a one-shot command when another process
+// holds the lease.
+var ErrNotLeader = errors.New("another process holds the lease")
+
+// ErrDaemonRunning is returned by a one-shot command when leader election
+// is not configured and a liquidator is running on the status port.
+var ErrDaemonRunning = errors.New("a liquidator is running on the status port and no lease is configured")
+
+// daemonProbeTimeout is how long a one-shot command waits for the health
+// check of a running liquidator.
+const daemonProbeTimeout = 2 * time.Second
+
+// NewLiquidator returns a liquidator for one-shot commands. The monitor
+// loop is not started, and Close must be called when done.
+func NewLiquidator(config *config.AppConfig) (*Liquidator, error) {
+	return newLiquidator(config)
+}
+
+// Close flushes the audit log and the notifier of a liquidator returned
+// by NewLiquidator.
+func (l *Liquidator) Close() error {
+
+	if err := l.audit.Close(); err != nil {
+		zap.L().Error("unable to close audit log", zap.Error(err))
+	}
+
+	return l.notifier.Close()
+}
+
+// acquireLease takes the lease before a one-shot command submits orders
+// or transfers, so it never acts at the same time as a running leader.
+// Without a lease, the command does not run while a liquidator is serving
+// on the status port. The returned func releases the lease.
+func (l *Liquidator) acquireLease() (release func(), err error) {
+
+	release = func() {}
+
+	if l.elector == nil {
+		if l.daemonRunning() {
+			err = ErrDaemonRunning
+		}
+		return
+	}
+
+	l.elector.Start()
+
+	release = func() {
+		if err := l.elector.Stop(); err != nil {
+			zap.L().Error("unable to release lease", zap.Error(err))
+		}
+	}
+
+	if !l.elector.IsLeader() {
+		release()
+		release = func() {}
+		err = ErrNotLeader
+	}
+
+	return
+}
+
+// daemonRunning returns true if a liquidator that has not halted answers
+// the health check on the status port.
+func (l *Liquidator) daemonRunning() bool {
+
+	if len(l.config.StatusPort) == 0 {
+		return false
+	}
+
+	client := &http.Client{Timeout: daemonProbeTimeout}
+
+	resp, err := client.Get(fmt.Sprintf("http://localhost:%s/health", l.config.StatusPort))
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == http.StatusOK
+}
+
+// awaitSubmitted polls the orders and conversions submitted by a one-shot
+// command until they finish, so their order, fill, and conversion records
+// are written to the audit log before the command exits. TWAP orders are
+// polled for up to their duration.
+func (l *Liquidator) awaitSubmitted() {
+
+	for {
+
+		l.checkOrders()
+
+		l.checkConversions()
+
+		if !l.working() {
+			return
+		}
+
+		zap.L().Info("waiting for submitted orders and conversions to finish")
+
+		time.Sleep(l.config.PollInterval())
+	}
+}
+
+// RunOnce makes a single pass over the trading balances and returns the
+// outcome for each asset once the submitted orders and conversions have
+// finished.
+func (l *Liquidator) RunOnce() ([]*Outcome, error) {
+
+	release, err := l.acquireLease()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	defer l.awaitSubmitted()
+
+	if err := l.describeCurrentState(); err != nil {
+		return nil, err
+	}
+
+	if !l.processBalances(l.currentBalances(), false) {
+		return l.Outcomes(), errors.New("pass stopped before all assets were processed")
+	}
+
+	return l.Outcomes(), nil
+}
+
+// Liquidate sells or converts the balance of a single asset, ignoring
+// the trigger values, and returns once the order or conversion has
+// finished. If the amount is positive, only that amount is sold, and it
+// must not be more than the balance less holds.
+func (l *Liquidator) Liquidate(symbol string, amount decimal.Decimal) (*Outcome, error) {
+
+	release, err := l.acquireLease()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	defer l.awaitSubmitted()
+
+	if err := l.describeCurrentState(); err != nil {
+		return nil, err
+	}
+
+	asset := l.balance(symbol)
+	if asset == nil {
+		return nil, fmt.Errorf("no trading balance: %s", symbol)
+	}
+
+	if amount.IsPositive() {
+		if asset, err = partialBalance(asset, amount); err != nil {
+			return nil, err
+		}
+	}
+
+	o, err := l.processAsset(asset, true)
+	l.recordOutcome(o)
+
+	return o, err
+}
+
+// Convert submits a conversion between two trading wallets and returns
+// once it has finished. The rounding digits of a configured route are
+// used if one matches.
+func (l *Liquidator) Convert(from, to string, amount decimal.Decimal) (o *Outcome, err error) {
+
+	from, to = strings.ToLower(from), strings.ToLower(to)
+
+	if from == to {
+		return nil, fmt.Errorf("cannot convert to the same symbol: %s", from)
+	}
+
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("conversion amount must be positive: %s", amount)
+	}
+
+	release, err := l.acquireLease()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	defer l.awaitSubmitted()
+
+	route := &conversionRoute{source: from, destination: to, digits: defaultConversionDigits}
+	if r := l.routes.lookup(from); r != nil && r.destination == to {
+		route.digits = r.digits
+	}
+
+	asset := &prime.Balance{Symbol: from, Amount: amount.String()}
+
+	o = newOutcome(asset)
+
+	rec := audit.NewDecision(asset)
+
+	defer func() {
+		if err != nil {
+			o.fail(err)
+		}
+		o.apply(rec)
+		l.appendAudit(rec)
+	}()
+
+	return l.processConversion(amount, asset, route, o)
+}
+
+// CancelAll cancels the open sell orders for fiat in the portfolio that
+// were submitted by the liquidator, optionally only for a single asset,
+// and returns the cancelled orders. Orders are matched by the client
+// order ids the liquidator generates or the orders it tracks. If all is
+// set, orders placed by other means, such as by hand, are also cancelled.
+func (l *Liquidator) CancelAll(symbol string, all bool) (cancelled []*prime.Order, err error) {
+
+	release, err := l.acquireLease()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var productId string
+	if len(symbol) > 0 {
+		productId = l.productId(&prime.Balance{Symbol: symbol})
+	}
+
+	orders, err := l.call.PrimeDescribeOpenOrders(productId)
+	if err != nil {
+		return nil, err
+	}
+
+	suffix := "-" + strings.ToUpper(l.config.FiatCurrencySymbol)
+
+	for _, order := range orders {
+
+		if order.Side != prime.OrderSideSell || !strings.HasSuffix(order.ProductId, suffix) {
+			continue
+		}
+
+		if !all && !caller.IsLiquidatorOrder(order.ClientOrderId) && !l.orderTracked(order.Id) {
+			continue
+		}
+
+		if err = l.call.PrimeCancelOrder(order.Id); err != nil {
+			return
+		}
+
+		zap.L().Info("cancelled order", zap.String("orderId", order.Id), zap.String("productId", order.ProductId))
+
+		cancelled = append(cancelled, order)
+	}
+
+	return
+}
+
+// balance returns the current trading balance for the symbol or nil.
+func (l *Liquidator) balance(symbol string) *prime.Balance {
+	for _, b := range l.currentBalances() {
+		if strings.EqualFold(b.Symbol, symbol) {
+			return b
+		}
+	}
+	return nil
+}
+
+// partialBalance returns a copy of the balance with the amount set and
+// no holds, so only that amount is sold.
+func partialBalance(asset *prime.Balance, amount decimal.Decimal) (*prime.Balance, error) {
+
+	total, err := asset.AmountNum()
+	if err != nil {
+		return nil, err
+	}
+
+	holds, err := asset.HoldsNum()
+	if err != nil {
+		return nil, err
+	}
+
+	if available := total.Sub(holds); amount.GreaterThan(available) {
+		return nil, fmt.Errorf("amount exceeds available balance: %s - available: %s", amount, available)
+	}
+
+	partial := *asset
+	partial.Amount = amount.String()
+	partial.Holds = "0"
+
+	return &partial, nil
+}
@@ -0,0 +1,335 @@
+/**
+ * Copyright 2023-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/audit"
+	"github.com/coinbase-samples/prime-liquidator-go/config"
+	"github.com/coinbase-samples/prime-liquidator-go/monitor/caller"
+	prime "github.com/coinbase-samples/prime-sdk-go"
+	"github.com/shopspring/decimal"
+)
+
+// commandCaller returns the configured balances, prices, price errors,
+// and open orders and counts the submissions. Other caller methods are
+// not implemented.
+type commandCaller struct {
+	catalogueCaller
+	balances    []*prime.Balance
+	prices      map[string]decimal.Decimal
+	priceErrors map[string]error
+	open        []*prime.Order
+	cancelled   []string
+	submitted   int
+}
+
+func (c *commandCaller) PrimeDescribeTradingBalances() ([]*prime.Balance, error) {
+	return c.balances, nil
+}
+
+func (c *commandCaller) ExchangeCurrentProductPrice(productId string) (decimal.Decimal, error) {
+	return c.prices[productId], c.priceErrors[productId]
+}
+
+func (c *commandCaller) PrimeCalculateOrderSize(product *prime.Product, amount, holds decimal.Decimal) (decimal.Decimal, error) {
+	return amount.Sub(holds), nil
+}
+
+func (c *commandCaller) PrimeCreateTwapOrder(
+	productId string,
+	value,
+	orderSize,
+	limitPrice decimal.Decimal,
+	duration time.Duration,
+	asset *prime.Balance,
+) (*prime.CreateOrderResponse, error) {
+	c.submitted++
+	return orderResponse("twap"), nil
+}
+
+func (c *commandCaller) PrimeCreateMarketOrder(
+	productId string,
+	value,
+	orderSize decimal.Decimal,
+	asset *prime.Balance,
+) (*prime.CreateOrderResponse, error) {
+	c.submitted++
+	return orderResponse("market"), nil
+}
+
+func (c *commandCaller) PrimeCreateConversion(
+	sourceWallet,
+	destinationWallet *prime.Wallet,
+	amount decimal.Decimal,
+	digits int32,
+) (*prime.CreateConversionResponse, error) {
+	c.submitted++
+	return &prime.CreateConversionResponse{ActivityId: "conversion"}, nil
+}
+
+func (c *commandCaller) PrimeDescribeOpenOrders(productId string) ([]*prime.Order, error) {
+	return c.open, nil
+}
+
+func (c *commandCaller) PrimeDescribeOrder(orderId string) (*prime.Order, error) {
+	return &prime.Order{Id: orderId, BaseQuantity: "3", FilledQuantity: "3"}, nil
+}
+
+func (c *commandCaller) PrimeCancelOrder(orderId string) error {
+	c.cancelled = append(c.cancelled, orderId)
+	return nil
+}
+
+func orderResponse(orderId string) *prime.CreateOrderResponse {
+	return &prime.CreateOrderResponse{
+		OrderId: orderId,
+		Request: &prime.CreateOrderRequest{Order: &prime.Order{ClientOrderId: "liquidator-" + orderId}},
+	}
+}
+
+func newCommandLiquidator(t *testing.T, call *commandCaller) *Liquidator {
+
+	c := &config.AppConfig{
+		FiatCurrencySymbol:     "usd",
+		TwapMinNotionalPerHour: "1000",
+		TwapDurationInMinutes:  "60",
+		TwapMaxDiscountPercent: decimal.RequireFromString("0.05"),
+	}
+
+	s, err := newSchedule("", "", "")
+	if err != nil {
+		t.Fatalf("cannot create schedule: %v", err)
+	}
+
+	routes, err := newConversionRoutes([]string{"usdc"}, c.FiatCurrencySymbol, "")
+	if err != nil {
+		t.Fatalf("cannot create conversion routes: %v", err)
+	}
+
+	triggers, err := newTriggerValues(decimal.Zero, "")
+	if err != nil {
+		t.Fatalf("cannot create trigger values: %v", err)
+	}
+
+	r := &recorder{}
+
+	return &Liquidator{
+		config:      c,
+		call:        call,
+		catalogue:   newCatalogue(call, time.Hour, time.Hour),
+		routes:      routes,
+		schedule:    s,
+		triggers:    triggers,
+		breaker:     newBreaker(0, 0),
+		audit:       r,
+		notifier:    r,
+		orders:      make(map[string]*trackedOrder),
+		conversions: make(map[string]*trackedConversion),
+		outcomes:    make(map[string]*Outcome),
+	}
+}
+
+func TestCancelAll(t *testing.T) {
+
+	cases := []struct {
+		description string
+		all         bool
+		expected    []string
+	}{
+		{
+			description: "TestCancelAllLiquidatorOrders",
+			expected:    []string{"eth-sell", "sol-sell", "tracked-sell"},
+		},
+		{
+			description: "TestCancelAllOrders",
+			all:         true,
+			expected:    []string{"eth-sell", "sol-sell", "tracked-sell", "desk-sell"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			call := &commandCaller{
+				open: []*prime.Order{
+					{Id: "eth-sell", ClientOrderId: "liquidator-1", ProductId: "ETH-USD", Side: prime.OrderSideSell},
+					{Id: "eth-buy", ClientOrderId: "liquidator-2", ProductId: "ETH-USD", Side: prime.OrderSideBuy},
+					{Id: "eth-btc-sell", ClientOrderId: "liquidator-3", ProductId: "ETH-BTC", Side: prime.OrderSideSell},
+					{Id: "sol-sell", ClientOrderId: "liquidator-4", ProductId: "SOL-USD", Side: prime.OrderSideSell},
+					{Id: "tracked-sell", ClientOrderId: "5", ProductId: "SOL-USD", Side: prime.OrderSideSell},
+					{Id: "desk-sell", ClientOrderId: "desk-6", ProductId: "SOL-USD", Side: prime.OrderSideSell},
+				},
+			}
+
+			l := newCommandLiquidator(t, call)
+			l.orders["tracked-sell"] = &trackedOrder{orderId: "tracked-sell"}
+
+			cancelled, err := l.CancelAll("", tt.all)
+			if err != nil {
+				t.Fatalf("test: %s - cannot cancel orders: %v", tt.description, err)
+			}
+
+			if len(cancelled) != len(tt.expected) || len(call.cancelled) != len(tt.expected) {
+				t.Fatalf("test: %s - expected: %v - received: %v", tt.description, tt.expected, call.cancelled)
+			}
+
+			for i, id := range tt.expected {
+				if call.cancelled[i] != id {
+					t.Errorf("test: %s - expected: %s - received: %s", tt.description, id, call.cancelled[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLiquidate(t *testing.T) {
+
+	call := &commandCaller{
+		catalogueCaller: catalogueCaller{
+			products: caller.ProductLookup{
+				"SOL-USD": &prime.Product{Id: "SOL-USD", QuoteIncrement: "0.01", QuoteMinSize: "1"},
+			},
+		},
+		balances: []*prime.Balance{{Symbol: "sol", Amount: "3", Holds: "0"}},
+		prices:   map[string]decimal.Decimal{"SOL-USD": decimal.NewFromInt(100)},
+	}
+
+	l := newCommandLiquidator(t, call)
+
+	o, err := l.Liquidate("sol", decimal.Zero)
+	if err != nil {
+		t.Fatalf("cannot liquidate: %v", err)
+	}
+
+	if o.Action != ActionOrder || call.submitted != 1 {
+		t.Fatalf("expected an order - received: %s - submitted: %d", o.Action, call.submitted)
+	}
+
+	// The order is polled until it fills, so its record is written before
+	// the command returns
+	if l.trackingOrders() {
+		t.Errorf("expected the order to be finished")
+	}
+
+	r := l.audit.(*recorder)
+
+	var filled int
+	for _, rec := range r.records {
+		if rec.Kind == audit.KindOrder && rec.Status == orderStatusFilled {
+			filled++
+		}
+	}
+
+	if filled != 1 {
+		t.Errorf("expected a filled order record - received: %d", filled)
+	}
+}
+
+func TestAcquireLease(t *testing.T) {
+
+	cases := []struct {
+		description string
+		status      int
+		expected    error
+	}{
+		{
+			description: "TestAcquireLeaseNoStatusPort",
+		},
+		{
+			description: "TestAcquireLeaseDaemonRunning",
+			status:      http.StatusOK,
+			expected:    ErrDaemonRunning,
+		},
+		{
+			description: "TestAcquireLeaseDaemonHalted",
+			status:      http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			l := newCommandLiquidator(t, &commandCaller{})
+
+			if tt.status != 0 {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path != "/health" {
+						t.Errorf("test: %s - unexpected path: %s", tt.description, r.URL.Path)
+					}
+					w.WriteHeader(tt.status)
+				}))
+				defer server.Close()
+
+				u, _ := url.Parse(server.URL)
+				l.config.StatusPort = u.Port()
+			}
+
+			release, err := l.acquireLease()
+			if err != tt.expected {
+				t.Errorf("test: %s - expected: %v - received: %v", tt.description, tt.expected, err)
+			}
+			release()
+		})
+	}
+}
+
+func TestPartialBalance(t *testing.T) {
+
+	cases := []struct {
+		description string
+		amount      string
+		expectErr   bool
+	}{
+		{description: "TestPartialBalanceBelowAvailable", amount: "1.5"},
+		{description: "TestPartialBalanceEqualToAvailable", amount: "2"},
+		{description: "TestPartialBalanceAboveAvailable", amount: "2.5", expectErr: true},
+	}
+
+	asset := &prime.Balance{Symbol: "eth", Amount: "3", Holds: "1"}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			partial, err := partialBalance(asset, decimal.RequireFromString(tt.amount))
+
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("test: %s - expected error", tt.description)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("test: %s - unexpected error: %v", tt.description, err)
+			}
+
+			if partial.Amount != tt.amount || partial.Holds != "0" {
+				t.Errorf("test: %s - expected: %s - received: %s holds: %s", tt.description, tt.amount, partial.Amount, partial.Holds)
+			}
+
+			if asset.Amount != "3" {
+				t.Errorf("test: %s - expected the balance to be unchanged - received: %s", tt.description, asset.Amount)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/audit"
+	"github.com/coinbase-samples/prime-liquidator-go/notify"
+	"go.uber.org/zap"
+)
+
+const (
+	activityStatusCompleted = "ACTIVITY_STATUS_COMPLETED"
+	activityStatusFailed    = "ACTIVITY_STATUS_FAILED"
+	activityStatusCancelled = "ACTIVITY_STATUS_CANCELLED"
+	activityStatusExpired   = "ACTIVITY_STATUS_EXPIRED"
+
+	// conversionTrackingLimit is how long a conversion is tracked before
+	// it is dropped without a final status.
+	conversionTrackingLimit = 24 * time.Hour
+
+	conversionSettled = "settled"
+	conversionFailed  = "failed"
+	conversionStuck   = "stuck"
+)
+
+// trackedConversion is a conversion submitted by the liquidator that is
+// polled until its activity completes or fails. The stuck flag is only
+// accessed by the monitor loop.
+type trackedConversion struct {
+	activityId  string
+	symbol      string
+	destination string
+	amount      string
+	submitted   time.Time
+	stuck       bool
+}
+
+func (l *Liquidator) trackConversion(activityId, symbol, destination, amount string) {
+
+	l.conversionsLock.Lock()
+	defer l.conversionsLock.Unlock()
+
+	l.conversions[activityId] = &trackedConversion{
+		activityId:  activityId,
+		symbol:      symbol,
+		destination: destination,
+		amount:      amount,
+		submitted:   time.Now(),
+	}
+}
+
+// checkConversions looks up the activity of each tracked conversion.
+// Completed conversions are recorded with the settled amount and fees.
+// Conversions that fail or are still pending after the stuck threshold
+// are logged, counted, and notified.
+func (l *Liquidator) checkConversions() {
+
+	for id, tracked := range l.trackedConversions() {
+
+		activity, err := l.call.PrimeDescribeActivity(id)
+		if err != nil {
+			zap.L().Error("unable to check conversion", zap.String("activityId", id), zap.Error(err))
+			continue
+		}
+
+		switch activity.Status {
+		case activityStatusCompleted:
+			l.conversionSettled(tracked, activity.ReferenceId)
+			l.untrackConversion(id)
+
+		case activityStatusFailed, activityStatusCancelled, activityStatusExpired:
+			l.conversionFailed(tracked, activity.Status)
+			l.untrackConversion(id)
+
+		default:
+			age := time.Since(tracked.submitted)
+
+			if age > conversionTrackingLimit {
+				zap.L().Warn("conversion no longer tracked", zap.String("activityId", id), zap.String("status", activity.Status))
+				l.untrackConversion(id)
+			} else if age > l.config.ConversionStuckAfter() && !tracked.stuck {
+				l.conversionStuck(tracked, activity.Status)
+			}
+		}
+	}
+}
+
+// conversionSettled looks up the conversion transaction for the settled
+// amount and fees. If the transaction cannot be found, the conversion is
+// still recorded as settled without them.
+func (l *Liquidator) conversionSettled(tracked *trackedConversion, transactionId string) {
+
+	rec := audit.NewConversion(tracked.activityId, tracked.symbol, tracked.amount)
+	rec.Destination = tracked.destination
+	rec.Status = conversionSettled
+
+	if len(transactionId) > 0 {
+		if tx, err := l.call.PrimeDescribeTransaction(transactionId); err != nil {
+			zap.L().Warn("unable to look up conversion transaction", zap.String("activityId", tracked.activityId), zap.Error(err))
+		} else {
+			rec.SettledAmount = tx.Amount
+			rec.Fees = tx.Fees
+		}
+	}
+
+	conversionCounts.Add(conversionSettled, 1)
+
+	zap.L().Info(
+		"conversion settled",
+		zap.String("activityId", tracked.activityId),
+		zap.String("symbol", tracked.symbol),
+		zap.String("amount", tracked.amount),
+		zap.String("settledAmount", rec.SettledAmount),
+		zap.String("fees", rec.Fees),
+	)
+
+	e := conversionEvent(notify.EventConversionSettled, tracked, conversionSettled)
+	e.SettledAmount = rec.SettledAmount
+	e.Fees = rec.Fees
+	l.notifier.Notify(e)
+
+	l.appendAudit(rec)
+}
+
+func (l *Liquidator) conversionFailed(tracked *trackedConversion, status string) {
+
+	conversionCounts.Add(conversionFailed, 1)
+
+	zap.L().Error(
+		"conversion failed",
+		zap.String("activityId", tracked.activityId),
+		zap.String("symbol", tracked.symbol),
+		zap.String("amount", tracked.amount),
+		zap.String("status", status),
+	)
+
+	l.notifier.Notify(conversionEvent(notify.EventConversionFailed, tracked, status))
+
+	rec := audit.NewConversion(tracked.activityId, tracked.symbol, tracked.amount)
+	rec.Destination = tracked.destination
+	rec.Status = conversionFailed
+	rec.Error = status
+	l.appendAudit(rec)
+}
+
+// conversionStuck is notified once for each conversion that has not
+// completed within the stuck threshold. The conversion is still tracked.
+func (l *Liquidator) conversionStuck(tracked *trackedConversion, status string) {
+
+	tracked.stuck = true
+
+	conversionCounts.Add(conversionStuck, 1)
+
+	zap.L().Warn(
+		"conversion stuck",
+		zap.String("activityId", tracked.activityId),
+		zap.String("symbol", tracked.symbol),
+		zap.Time("submitted", tracked.submitted),
+		zap.String("status", status),
+	)
+
+	l.notifier.Notify(conversionEvent(notify.EventConversionStuck, tracked, status))
+}
+
+// trackedConversions returns a copy of the tracked conversions so they
+// can be checked without holding the lock during Prime calls.
+func (l *Liquidator) trackedConversions() map[string]*trackedConversion {
+
+	l.conversionsLock.Lock()
+	defer l.conversionsLock.Unlock()
+
+	conversions := make(map[string]*trackedConversion, len(l.conversions))
+	for id, tracked := range l.conversions {
+		conversions[id] = tracked
+	}
+	return conversions
+}
+
+// trackingConversions returns true if there are conversions that have
+// not completed or failed.
+func (l *Liquidator) trackingConversions() bool {
+	l.conversionsLock.Lock()
+	defer l.conversionsLock.Unlock()
+	return len(l.conversions) > 0
+}
+
+func (l *Liquidator) untrackConversion(id string) {
+	l.conversionsLock.Lock()
+	defer l.conversionsLock.Unlock()
+	delete(l.conversions, id)
+}
+
+func (l *Liquidator) appendAudit(rec *audit.Record) {
+	if err := l.audit.Append(rec); err != nil {
+		zap.L().Error("unable to write audit record", zap.String("symbol", rec.Symbol), zap.Error(err))
+	}
+}
+
+func conversionEvent(eventType notify.EventType, tracked *trackedConversion, status string) *notify.Event {
+	e := notify.NewEvent(eventType)
+	e.Symbol = tracked.symbol
+	e.ActivityId = tracked.activityId
+	e.Size = tracked.amount
+	e.Status = status
+	return e
+}
@@ -0,0 +1,147 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/audit"
+	"github.com/coinbase-samples/prime-liquidator-go/config"
+	"github.com/coinbase-samples/prime-liquidator-go/monitor/caller"
+	"github.com/coinbase-samples/prime-liquidator-go/notify"
+	prime "github.com/coinbase-samples/prime-sdk-go"
+)
+
+// activityCaller returns activities and transactions by id. Other
+// caller methods are not implemented.
+type activityCaller struct {
+	caller.Caller
+	activities   map[string]*prime.Activity
+	transactions map[string]*prime.Transaction
+}
+
+func (c *activityCaller) PrimeDescribeActivity(activityId string) (*prime.Activity, error) {
+	return c.activities[activityId], nil
+}
+
+func (c *activityCaller) PrimeDescribeTransaction(transactionId string) (*prime.Transaction, error) {
+	return c.transactions[transactionId], nil
+}
+
+// recorder captures audit records and notification events.
+type recorder struct {
+	mu      sync.Mutex
+	records []*audit.Record
+	events  []*notify.Event
+}
+
+func (r *recorder) Append(rec *audit.Record) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, rec)
+	return nil
+}
+
+func (r *recorder) Notify(e *notify.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+func (r *recorder) Close() error { return nil }
+
+func TestCheckConversions(t *testing.T) {
+
+	call := &activityCaller{
+		activities: map[string]*prime.Activity{
+			"settled": {Id: "settled", Status: activityStatusCompleted, ReferenceId: "tx-1"},
+			"failed":  {Id: "failed", Status: activityStatusFailed},
+			"stuck":   {Id: "stuck", Status: "ACTIVITY_STATUS_PROCESSING"},
+			"pending": {Id: "pending", Status: "ACTIVITY_STATUS_PROCESSING"},
+		},
+		transactions: map[string]*prime.Transaction{
+			"tx-1": {Id: "tx-1", Amount: "99.95", Fees: "0.05"},
+		},
+	}
+
+	r := &recorder{}
+
+	l := &Liquidator{
+		config:      &config.AppConfig{ConversionStuckInMinutes: "15"},
+		call:        call,
+		audit:       r,
+		notifier:    r,
+		conversions: make(map[string]*trackedConversion),
+	}
+
+	for id := range call.activities {
+		l.trackConversion(id, "usdc", "usd", "100")
+	}
+
+	l.conversions["stuck"].submitted = time.Now().Add(-time.Hour)
+
+	l.checkConversions()
+	l.checkConversions()
+
+	cases := []struct {
+		description string
+		eventType   notify.EventType
+		activityId  string
+		events      int
+		tracked     bool
+	}{
+		{description: "TestCheckConversionsSettled", eventType: notify.EventConversionSettled, activityId: "settled", events: 1},
+		{description: "TestCheckConversionsFailed", eventType: notify.EventConversionFailed, activityId: "failed", events: 1},
+		{description: "TestCheckConversionsStuck", eventType: notify.EventConversionStuck, activityId: "stuck", events: 1, tracked: true},
+		{description: "TestCheckConversionsPending", activityId: "pending", tracked: true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			var count int
+			for _, e := range r.events {
+				if e.ActivityId == tt.activityId {
+					count++
+					if e.Type != tt.eventType {
+						t.Errorf("test: %s - expected: %s - received: %s", tt.description, tt.eventType, e.Type)
+					}
+				}
+			}
+
+			if count != tt.events {
+				t.Errorf("test: %s - expected: %d events - received: %d", tt.description, tt.events, count)
+			}
+
+			if _, found := l.trackedConversions()[tt.activityId]; found != tt.tracked {
+				t.Errorf("test: %s - expected tracked: %t - received: %t", tt.description, tt.tracked, found)
+			}
+		})
+	}
+
+	if len(r.records) != 2 {
+		t.Fatalf("expected settled and failed records - received: %d", len(r.records))
+	}
+
+	for _, rec := range r.records {
+		if rec.ActivityId == "settled" && (rec.SettledAmount != "99.95" || rec.Fees != "0.05") {
+			t.Errorf("expected settled amount and fees - received: %+v", rec)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"errors"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/monitor/caller"
+	"github.com/coinbase-samples/prime-liquidator-go/notify"
+	"go.uber.org/zap"
+)
+
+const (
+	rateLimitBackoffMin = 5 * time.Second
+	rateLimitBackoffMax = 2 * time.Minute
+)
+
+// handleError applies the handling for the kind of error and returns
+// true if the current pass over the balances should stop. Authentication
+// failures halt the liquidator because retrying cannot succeed without
+// operator action. Rate limits back off exponentially until a pass
+// completes. Transient failures, rejections, and invalid products only
+// affect the current asset. It is safe to call from multiple workers.
+func (l *Liquidator) handleError(err error) bool {
+
+	switch {
+	case errors.Is(err, caller.ErrAuth):
+		l.halt(err)
+		return true
+
+	case errors.Is(err, caller.ErrRateLimited):
+		l.rateLimited.Store(true)
+		return true
+	}
+
+	return false
+}
+
+// backOff sleeps if a rate limit was hit since the last call. The
+// backoff doubles each time until a pass completes. This is called
+// by the monitor loop once the workers have stopped, so concurrent
+// rate limit errors result in a single backoff.
+func (l *Liquidator) backOff() {
+
+	if !l.rateLimited.Swap(false) {
+		return
+	}
+
+	l.rateLimitBackoff *= 2
+	if l.rateLimitBackoff < rateLimitBackoffMin {
+		l.rateLimitBackoff = rateLimitBackoffMin
+	} else if l.rateLimitBackoff > rateLimitBackoffMax {
+		l.rateLimitBackoff = rateLimitBackoffMax
+	}
+
+	zap.L().Warn("rate limited - backing off", zap.Duration("backoff", l.rateLimitBackoff))
+	time.Sleep(l.rateLimitBackoff)
+}
+
+// halt stops the monitor loop. The process keeps running so the failure
+// is visible until it is restarted. Only the first halt is notified when
+// several workers fail at once.
+func (l *Liquidator) halt(err error) {
+
+	if !l.running.Swap(false) {
+		return
+	}
+
+	zap.L().Error("halting liquidator", zap.Error(err))
+
+	l.notifier.Notify(notify.NewEvent(notify.EventHalted).WithError(err))
+}
@@ -0,0 +1,56 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"github.com/coinbase-samples/prime-liquidator-go/notify"
+	"go.uber.org/zap"
+)
+
+// leading returns true if leader election is disabled or this process
+// holds the lease. Only the leader processes balances.
+func (l *Liquidator) leading() bool {
+	return l.elector == nil || l.elector.IsLeader()
+}
+
+// leadershipChanged is called by the elector when this process gains or
+// loses the lease. Leases taken by one-shot commands are not notified.
+func (l *Liquidator) leadershipChanged(leader bool) {
+
+	if !l.running.Load() {
+		return
+	}
+
+	eventType := notify.EventLeaderLost
+	if leader {
+		eventType = notify.EventLeaderElected
+
+		// Orders and transfers submitted by the previous leader are
+		// loaded before the next checks and sweeps
+		l.ordersLoaded.Store(false)
+		l.transfersLoaded.Store(false)
+		if l.proceeds != nil {
+			l.proceeds.loaded.Store(false)
+		}
+	}
+
+	zap.L().Info("leader election", zap.String("state", string(eventType)))
+
+	l.notifier.Notify(notify.NewEvent(eventType))
+
+	l.wakeUp()
+}
@@ -17,40 +17,84 @@
 package monitor
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/coinbase-samples/prime-liquidator-go/audit"
 	"github.com/coinbase-samples/prime-liquidator-go/config"
+	"github.com/coinbase-samples/prime-liquidator-go/feed"
+	"github.com/coinbase-samples/prime-liquidator-go/lease"
 	"github.com/coinbase-samples/prime-liquidator-go/monitor/caller"
+	"github.com/coinbase-samples/prime-liquidator-go/notify"
 	prime "github.com/coinbase-samples/prime-sdk-go"
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 )
 
 type Liquidator struct {
-	config         *config.AppConfig
-	convertSymbols caller.ConvertSymbols
-	balances       []*prime.Balance
-	products       caller.ProductLookup
-	wallets        caller.WalletLookup
-	call           caller.Caller
-	stopWaitGroup  sync.WaitGroup
-	running        atomic.Bool
+	config           *config.AppConfig
+	routes           conversionRoutes
+	balances         []*prime.Balance
+	balancesLock     sync.RWMutex
+	catalogue        *catalogue
+	poller           *poller
+	call             caller.Caller
+	notifier         notify.Notifier
+	audit            audit.Store
+	orders           map[string]*trackedOrder
+	ordersLock       sync.Mutex
+	ordersLoaded     atomic.Bool
+	conversions      map[string]*trackedConversion
+	conversionsLock  sync.Mutex
+	transfers        map[string]*trackedTransfer
+	transfersLock    sync.Mutex
+	transfersLoaded  atomic.Bool
+	outcomes         map[string]*Outcome
+	outcomesLock     sync.Mutex
+	paused           atomic.Bool
+	breaker          *breaker
+	schedule         *schedule
+	triggers         *triggerValues
+	lastDustSweep    time.Time
+	lastVaultSweep   time.Time
+	deposits         *depositSweep
+	proceeds         *proceedsSweep
+	rateLimitBackoff time.Duration
+	rateLimited      atomic.Bool
+	feed             *feed.Feed
+	elector          *lease.Elector
+	wake             chan struct{}
+	stopWaitGroup    sync.WaitGroup
+	running          atomic.Bool
+	dryRun           bool
 }
 
 // StartLiquidator continuously monitors for assets in hot/trading wallets
 // and coverts them to fiat.
 func StartLiquidator(config *config.AppConfig) (*Liquidator, error) {
 
-	l := newLiquidator(config)
+	l, err := newLiquidator(config)
+	if err != nil {
+		return nil, err
+	}
 
 	l.stopWaitGroup.Add(1)
 
 	l.running.Store(true)
 
+	if l.elector != nil {
+		l.elector.Start()
+	}
+
+	if l.feed != nil {
+		l.feed.Start()
+	}
+
 	go l.monitor()
 
 	return l, nil
@@ -60,25 +104,97 @@ func StopLiquidator(l *Liquidator) error {
 
 	l.running.Store(false)
 
+	l.wakeUp()
+
 	l.stopWaitGroup.Wait()
 
-	return nil
+	if l.elector != nil {
+		if err := l.elector.Stop(); err != nil {
+			zap.L().Error("unable to release lease", zap.Error(err))
+		}
+	}
+
+	if l.feed != nil {
+		if err := l.feed.Close(); err != nil {
+			zap.L().Error("unable to close prime feed", zap.Error(err))
+		}
+	}
+
+	if err := l.audit.Close(); err != nil {
+		zap.L().Error("unable to close audit log", zap.Error(err))
+	}
+
+	return l.notifier.Close()
 
 }
 
 // newLiquidator returns a new Liquidator struct pointer.
-func newLiquidator(config *config.AppConfig) (l *Liquidator) {
+func newLiquidator(config *config.AppConfig) (l *Liquidator, err error) {
 
 	l = &Liquidator{
-		config:         config,
-		convertSymbols: make(caller.ConvertSymbols),
-		call:           caller.NewCaller(config),
+		config:        config,
+		call:          caller.NewCaller(config),
+		notifier:      notify.NewNotifier(config),
+		orders:        make(map[string]*trackedOrder),
+		conversions:   make(map[string]*trackedConversion),
+		transfers:     make(map[string]*trackedTransfer),
+		outcomes:      make(map[string]*Outcome),
+		breaker:       newBreaker(config.OrderBreakerThreshold(), config.OrderBreakerCoolDown()),
+		lastDustSweep: time.Now(),
+		wake:          make(chan struct{}, 1),
+		poller:        newPoller(config.PollMinInterval(), config.PollInterval()),
+	}
+
+	if len(config.PrimeFeedUrl) > 0 {
+		l.feed = feed.NewFeed(config.PrimeFeedUrl, config.PrimeClient.Credentials, l.orderUpdated)
+	}
+
+	l.catalogue = newCatalogue(l.call, config.WalletsRefreshInterval(), config.ProductsRefreshInterval())
+
+	l.schedule, err = newSchedule(
+		config.TradingWindowsArray,
+		config.AssetTradingWindowsArray,
+		config.BlackoutDatesArray,
+	)
+	if err != nil {
+		err = fmt.Errorf("cannot parse trading schedule: %w", err)
+		return
+	}
+
+	l.routes, err = newConversionRoutes(
+		config.ConvertSymbols(),
+		config.FiatCurrencySymbol,
+		config.ConversionRoutesArray,
+	)
+	if err != nil {
+		err = fmt.Errorf("cannot parse conversion routes: %w", err)
+		return
+	}
+
+	l.triggers, err = newTriggerValues(config.TriggerMinValue(), config.AssetTriggerMinValuesArray)
+	if err != nil {
+		err = fmt.Errorf("cannot parse trigger values: %w", err)
+		return
+	}
+
+	l.proceeds, err = newProceedsSweep(config)
+	if err != nil {
+		err = fmt.Errorf("cannot configure proceeds sweep: %w", err)
+		return
+	}
+
+	leaderLease, err := lease.NewLease(config)
+	if err != nil {
+		err = fmt.Errorf("cannot create lease: %w", err)
+		return
 	}
 
-	for _, s := range config.ConvertSymbols() {
-		l.convertSymbols.Add(s)
+	if leaderLease != nil {
+		l.elector = lease.NewElector(leaderLease, config.LeaseTtl(), l.leadershipChanged)
 	}
 
+	l.audit, err = audit.NewStore(config)
+
 	return
 }
 
@@ -95,159 +211,425 @@ func (l *Liquidator) monitor() {
 		if !l.running.Load() {
 			break
 		}
+
+		// Standbys wait for the leader to release the lease or let it expire
+		if !l.leading() {
+			l.wait(l.config.PollInterval())
+			continue
+		}
+
+		if !l.ordersLoaded.Load() {
+			if err := l.loadOpenOrders(); err != nil {
+				zap.L().Error("unable to load open orders", zap.Error(err))
+				l.notifier.Notify(notify.NewEvent(notify.EventLoopError).WithError(err))
+			} else {
+				l.ordersLoaded.Store(true)
+			}
+		}
+
+		l.checkOrders()
+
+		l.checkConversions()
+
+		l.checkTransfers()
+
+		if l.vaultSweepDue(time.Now()) {
+			if err := l.sweepVaults(time.Now()); err != nil {
+				zap.L().Error("unable to sweep vault wallets", zap.Error(err))
+				l.notifier.Notify(notify.NewEvent(notify.EventLoopError).WithError(err))
+			}
+			l.lastVaultSweep = time.Now()
+		}
+
+		if !l.schedule.anyOpen(time.Now()) {
+			l.pause(true)
+			l.wait(l.config.PollInterval())
+			continue
+		}
+
+		l.pause(false)
+
 		if err := l.describeCurrentState(); err != nil {
 			zap.L().Error("unable to describe current state", zap.Error(err))
-			time.Sleep(5 * time.Second)
+			l.notifier.Notify(notify.NewEvent(notify.EventLoopError).WithError(err))
+			if !l.handleError(err) {
+				l.wait(l.config.ErrorInterval())
+			}
+			l.backOff()
 			continue
 		}
 
-		for _, asset := range l.balances {
-			if err := l.processAsset(asset); err != nil {
-				zap.L().Error("unable to process assets", zap.Error(err))
-			}
-			time.Sleep(500 * time.Millisecond)
+		sweep := l.dustSweepDue(time.Now())
+		if sweep {
+			zap.L().Info("dust sweep", zap.Time("lastDustSweep", l.lastDustSweep))
 		}
 
-		time.Sleep(5 * time.Second)
+		if !l.processBalances(l.currentBalances(), sweep) {
+			l.backOff()
+			continue
+		}
+
+		l.rateLimitBackoff = 0
+
+		if sweep {
+			l.lastDustSweep = time.Now()
+		}
+
+		if err := l.sweepProceeds(time.Now()); err != nil {
+			zap.L().Error("unable to sweep proceeds", zap.Error(err))
+			l.notifier.Notify(notify.NewEvent(notify.EventLoopError).WithError(err))
+			l.handleError(err)
+		}
+
+		l.wait(l.pollInterval())
 	}
 }
 
-// describeCurrentState lookups up the trading wallets, balances,
-// and products and sets updates the state on the struct.
-func (l *Liquidator) describeCurrentState() (err error) {
+// recordOutcome counts the outcome, logs it if it differs from the
+// previous outcome for the asset, and keeps it for the status.
+func (l *Liquidator) recordOutcome(o *Outcome) {
 
-	l.wallets, err = l.call.PrimeDescribeTradingWallets()
-	if err != nil {
+	countOutcome(o)
+
+	l.outcomesLock.Lock()
+	defer l.outcomesLock.Unlock()
+
+	if o.changed(l.outcomes[o.Symbol]) && o.Action != ActionFailed {
+		zap.L().Info(
+			"asset outcome",
+			zap.String("symbol", o.Symbol),
+			zap.String("action", string(o.Action)),
+			zap.String("skipReason", string(o.SkipReason)),
+			zap.String("orderType", o.OrderType),
+			zap.String("clientOrderId", o.ClientOrderId),
+		)
+	}
+
+	l.outcomes[o.Symbol] = o
+}
+
+// Outcomes returns the most recent outcome for each asset, sorted by
+// symbol.
+func (l *Liquidator) Outcomes() []*Outcome {
+
+	l.outcomesLock.Lock()
+	defer l.outcomesLock.Unlock()
+
+	outcomes := make([]*Outcome, 0, len(l.outcomes))
+	for _, o := range l.outcomes {
+		outcomes = append(outcomes, o)
+	}
+
+	sort.Slice(outcomes, func(i, j int) bool { return outcomes[i].Symbol < outcomes[j].Symbol })
+
+	return outcomes
+}
+
+// pause notifies when the schedule closes or reopens.
+func (l *Liquidator) pause(paused bool) {
+	if l.paused.Swap(paused) == paused {
 		return
 	}
 
-	l.products, err = l.call.PrimeDescribeProducts()
-	if err != nil {
+	eventType := notify.EventResumed
+	if paused {
+		eventType = notify.EventPaused
+	}
+
+	zap.L().Info("trading schedule", zap.String("state", string(eventType)))
+
+	l.notifier.Notify(notify.NewEvent(eventType))
+}
+
+// describeCurrentState refreshes the cached trading wallets and
+// products if they are stale and looks up the trading balances.
+func (l *Liquidator) describeCurrentState() (err error) {
+
+	if err = l.catalogue.refresh(time.Now()); err != nil {
 		return
 	}
 
-	l.balances, err = l.call.PrimeDescribeTradingBalances()
+	balances, err := l.call.PrimeDescribeTradingBalances()
 	if err != nil {
 		return
 	}
 
+	l.balancesLock.Lock()
+	defer l.balancesLock.Unlock()
+
+	l.balances = balances
+
 	return
 }
 
-// processConversion looks up the stablecoin and fiat wallets and then
-// submits a Prime conversion request.
-func (l Liquidator) processConversion(
+func (l *Liquidator) currentBalances() []*prime.Balance {
+	l.balancesLock.RLock()
+	defer l.balancesLock.RUnlock()
+	return l.balances
+}
+
+// processConversion looks up the source and destination wallets of the
+// route and then submits a Prime conversion request.
+func (l *Liquidator) processConversion(
 	amount decimal.Decimal,
 	asset *prime.Balance,
-) error {
+	route *conversionRoute,
+	o *Outcome,
+) (*Outcome, error) {
 
-	fiatWallet := l.wallets.Lookup(l.config.FiatCurrencySymbol)
-	if fiatWallet == nil {
-		return fmt.Errorf("fiat wallet not found: %s", l.config.FiatCurrencySymbol)
+	if amount.LessThan(route.minimum) {
+		return o.skip(SkipBelowConversionMinimum), nil
+	}
+
+	sourceWallet, err := l.catalogue.wallet(route.source)
+	if err != nil {
+		return o, err
 	}
 
-	stablecoinWallet := l.wallets.Lookup(asset.Symbol)
-	if stablecoinWallet == nil {
-		return fmt.Errorf("stablecoin wallet not found: %s", asset.Symbol)
+	if sourceWallet == nil {
+		return o, fmt.Errorf("source wallet not found: %s", route.source)
 	}
 
-	return l.call.PrimeCreateConversion(stablecoinWallet, fiatWallet, amount)
+	destinationWallet, err := l.catalogue.wallet(route.destination)
+	if err != nil {
+		return o, err
+	}
+
+	if destinationWallet == nil {
+		return o, fmt.Errorf("destination wallet not found: %s", route.destination)
+	}
+
+	if l.dryRun {
+		return o.plan(), nil
+	}
+
+	e := notify.NewEvent(notify.EventConversionSubmitted)
+	e.Symbol = asset.Symbol
+	e.Value = amount.String()
+
+	response, err := l.call.PrimeCreateConversion(sourceWallet, destinationWallet, amount, route.digits)
+	if errors.Is(err, caller.ErrZeroConversionAmount) {
+		return o.skip(SkipConversionRoundsToZero), nil
+	}
+
+	var duplicate *caller.DuplicateConversionError
+	if errors.As(err, &duplicate) {
+		o.ActivityId = duplicate.ActivityId
+		return o.skip(SkipDuplicateConversion), nil
+	}
+
+	if err != nil {
+		e.Type = notify.EventConversionFailed
+		l.notifier.Notify(e.WithError(err))
+		return o, err
+	}
+
+	o.Action = ActionConversion
+	o.ActivityId = response.ActivityId
+
+	l.trackConversion(response.ActivityId, asset.Symbol, route.destination, response.Request.Amount)
+
+	e.ActivityId = response.ActivityId
+	e.Size = response.Request.Amount
+	l.notifier.Notify(e)
+
+	return o, nil
+}
+
+// dustSweepDue returns true if the dust sweep is enabled and the
+// interval has elapsed since the last sweep.
+func (l *Liquidator) dustSweepDue(now time.Time) bool {
+	interval := l.config.DustSweepInterval()
+	if interval <= 0 {
+		return false
+	}
+	return now.Sub(l.lastDustSweep) >= interval
 }
 
 // processAsset takes an asset and either creates a sell order for fiat or
-// issues a conversion request if the asset is a stablecoin
-func (l Liquidator) processAsset(asset *prime.Balance) error {
-	if isFiat(asset.Symbol) {
-		return nil
+// issues a conversion request if the asset has a conversion route. Balances with
+// a value below the asset trigger are only processed by a dust sweep. The
+// outcome describes the action taken or why the asset was skipped. The
+// inputs and the outcome are written to the audit log.
+func (l *Liquidator) processAsset(asset *prime.Balance, sweep bool) (o *Outcome, err error) {
+
+	o = newOutcome(asset)
+
+	rec := audit.NewDecision(asset)
+	rec.Sweep = sweep
+
+	defer func() {
+		if err != nil {
+			o.fail(err)
+		}
+		o.apply(rec)
+		l.appendAudit(rec)
+	}()
+
+	route := l.routes.lookup(asset.Symbol)
+
+	if route == nil && isFiat(asset.Symbol) {
+		return o.skip(SkipFiat), nil
+	}
+
+	if !l.schedule.isOpen(asset.Symbol, time.Now()) {
+		return o.skip(SkipOutsideTradingWindow), nil
 	}
 
 	amount, err := asset.AmountNum()
 	if err != nil {
-		return err
+		return o, err
 	}
 
 	if amount.IsZero() {
-		return nil
+		return o.skip(SkipZeroAmount), nil
 	}
 
-	// Check for stablecoins that need to be converted
-	if l.convertSymbols.Is(asset.Symbol) {
-		return l.processConversion(amount, asset)
+	// Check for balances that need to be converted
+	if route != nil {
+		rec.Destination = route.destination
+		// Conversions are 1:1, so the trigger compares the source amount
+		if !sweep && l.triggers.below(asset.Symbol, amount) {
+			return o.skip(SkipBelowTriggerValue), nil
+		}
+		return l.processConversion(amount, asset, route, o)
 	}
 
 	productId := l.productId(asset)
+	rec.ProductId = productId
 
 	price, err := l.call.ExchangeCurrentProductPrice(productId)
 	if err != nil {
-		return fmt.Errorf("cannot get exchange price: %s - err: %w", productId, err)
+		return o, fmt.Errorf("cannot get exchange price: %s - err: %w", productId, err)
+	}
+
+	rec.Price = price.String()
+
+	product, err := l.catalogue.product(productId)
+	if err != nil {
+		return o, err
 	}
 
-	product := l.products.Lookup(productId)
 	if product == nil {
-		return fmt.Errorf("Unknown product id: %s", productId)
+		return o, fmt.Errorf("unknown product id: %s - %w", productId, caller.ErrInvalidProduct)
 	}
 
+	rec.SetProduct(product)
+
 	holds, err := asset.HoldsNum()
 	if err != nil {
-		return err
+		return o, err
 	}
 
 	orderSize, err := l.call.PrimeCalculateOrderSize(product, amount, holds)
 	if err != nil {
-		return err
+		return o, err
 	}
 
+	rec.OrderSize = orderSize.String()
+
 	if orderSize.IsZero() {
-		return nil
+		return o.skip(SkipZeroOrderSize), nil
 	}
 
 	value := price.Mul(orderSize)
 
+	rec.Value = value.String()
+
 	if value.IsZero() {
-		return nil
+		return o.skip(SkipZeroValue), nil
 	}
 
 	quoteMin, err := product.QuoteMinSizeNum()
 	if err != nil {
-		return err
+		return o, err
 	}
 
 	// Ensure that that the order value is is equal to or greater than the quote min size
 	if value.Cmp(quoteMin) < 0 {
-		return nil
+		return o.skip(SkipBelowQuoteMinSize), nil
 	}
 
+	// Let balances accumulate until they reach the trigger or are swept
+	if !sweep && l.triggers.below(asset.Symbol, value) {
+		return o.skip(SkipBelowTriggerValue), nil
+	}
+
+	duration := l.twapDuration(asset.Symbol)
+
 	// Check to see if the size of the order fits into the TWAP requirements
-	if meetsTwapRequirements(value, l.config.TwapMinNotional(), l.config.TwapDuration()) {
+	if useTwap(value, l.config.TwapMinNotional(), l.config.TwapDuration(), duration) {
+
+		o.OrderType = prime.OrderTypeTwap
+
+		rec.Duration = duration.String()
+
+		// Wait for the next trading window if the current one is about to close
+		if duration < minTwapDuration {
+			return o.skip(SkipTradingWindowClosing), nil
+		}
 
 		limitPrice, err := l.calculateTwapLimitPrice(product, price)
 		if err != nil {
-			return err
+			return o, err
 		}
 
-		return l.call.PrimeCreateTwapOrder(
+		rec.LimitPrice = limitPrice.String()
+
+		if l.dryRun {
+			return o.plan(), nil
+		}
+
+		if !l.breaker.allow(time.Now()) {
+			return o.skip(SkipOrderBreakerOpen), nil
+		}
+
+		response, err := l.call.PrimeCreateTwapOrder(
 			productId,
 			value,
 			orderSize,
 			limitPrice,
+			duration,
 			asset,
 		)
+
+		return l.trackOrder(response, err, prime.OrderTypeTwap, productId, price, value, orderSize, limitPrice, duration, asset, o)
+	}
+
+	o.OrderType = prime.OrderTypeMarket
+
+	if l.dryRun {
+		return o.plan(), nil
+	}
+
+	if !l.breaker.allow(time.Now()) {
+		return o.skip(SkipOrderBreakerOpen), nil
 	}
 
 	// Create a market order
-	return l.call.PrimeCreateMarketOrder(
+	response, err := l.call.PrimeCreateMarketOrder(
 		productId,
 		value,
 		orderSize,
 		asset,
 	)
 
+	return l.trackOrder(response, err, prime.OrderTypeMarket, productId, price, value, orderSize, decimal.Zero, 0, asset, o)
+}
+
+// twapDuration returns the configured TWAP duration, shortened if
+// needed so the order expires before the asset's trading window
+// closes or the next blackout starts.
+func (l *Liquidator) twapDuration(symbol string) time.Duration {
+	start := time.Now().UTC()
+	expiry := l.schedule.clampExpiry(symbol, start, start.Add(l.config.TwapDuration()))
+	return expiry.Sub(start)
 }
 
 // calculateTwapLimitPrice looks at the product, current
 // price, and max discount and returns the adjusted TWAP
 // price X% the most recent Exchange lookup.
-func (l Liquidator) calculateTwapLimitPrice(
+func (l *Liquidator) calculateTwapLimitPrice(
 	product *prime.Product,
 	price decimal.Decimal,
 ) (limitPrice decimal.Decimal, err error) {
@@ -263,7 +645,7 @@ func (l Liquidator) calculateTwapLimitPrice(
 	return
 }
 
-func (l Liquidator) adjustTwapLimitPrice(
+func (l *Liquidator) adjustTwapLimitPrice(
 	price,
 	quoteIncrement decimal.Decimal,
 ) decimal.Decimal {
@@ -276,6 +658,6 @@ func (l Liquidator) adjustTwapLimitPrice(
 	return quo.Floor().Mul(quoteIncrement)
 }
 
-func (l Liquidator) productId(asset *prime.Balance) string {
+func (l *Liquidator) productId(asset *prime.Balance) string {
 	return fmt.Sprintf("%s-%s", strings.ToUpper(asset.Symbol), strings.ToUpper(l.config.FiatCurrencySymbol))
 }
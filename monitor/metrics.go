@@ -0,0 +1,43 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"expvar"
+)
+
+// Counters are published with expvar and are available as JSON from
+// /debug/vars on any HTTP server that uses the default mux.
+var (
+	actionCounts       = expvar.NewMap("liquidator_actions")
+	skipReasonCounts   = expvar.NewMap("liquidator_skip_reasons")
+	conversionCounts   = expvar.NewMap("liquidator_conversions")
+	transferCounts     = expvar.NewMap("liquidator_transfers")
+	orderBreakerState  = expvar.NewString("liquidator_order_breaker_state")
+	orderBreakerOpened = expvar.NewInt("liquidator_order_breaker_opened")
+)
+
+func init() {
+	orderBreakerState.Set(string(BreakerClosed))
+}
+
+func countOutcome(o *Outcome) {
+	actionCounts.Add(string(o.Action), 1)
+	if o.Action == ActionSkip {
+		skipReasonCounts.Add(string(o.SkipReason), 1)
+	}
+}
@@ -0,0 +1,107 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/audit"
+	prime "github.com/coinbase-samples/prime-sdk-go"
+)
+
+type Action string
+
+const (
+	ActionOrder      Action = "order"
+	ActionConversion Action = "conversion"
+	ActionSkip       Action = "skip"
+	ActionFailed     Action = "failed"
+	ActionPlanned    Action = "planned"
+)
+
+type SkipReason string
+
+const (
+	SkipFiat                   SkipReason = "fiat"
+	SkipOutsideTradingWindow   SkipReason = "outside_trading_window"
+	SkipZeroAmount             SkipReason = "zero_amount"
+	SkipBelowTriggerValue      SkipReason = "below_trigger_value"
+	SkipZeroOrderSize          SkipReason = "zero_order_size"
+	SkipZeroValue              SkipReason = "zero_value"
+	SkipBelowQuoteMinSize      SkipReason = "below_quote_min_size"
+	SkipTradingWindowClosing   SkipReason = "trading_window_closing"
+	SkipDuplicateOrder         SkipReason = "duplicate_order"
+	SkipConversionRoundsToZero SkipReason = "conversion_rounds_to_zero"
+	SkipDuplicateConversion    SkipReason = "duplicate_conversion"
+	SkipBelowConversionMinimum SkipReason = "below_conversion_minimum"
+	SkipOrderBreakerOpen       SkipReason = "order_breaker_open"
+)
+
+// Outcome is the decision taken when processing an asset on a single
+// loop iteration.
+type Outcome struct {
+	Symbol        string     `json:"symbol"`
+	Time          time.Time  `json:"time"`
+	Action        Action     `json:"action"`
+	SkipReason    SkipReason `json:"skip_reason,omitempty"`
+	OrderType     string     `json:"order_type,omitempty"`
+	ClientOrderId string     `json:"client_order_id,omitempty"`
+	OrderId       string     `json:"order_id,omitempty"`
+	ActivityId    string     `json:"activity_id,omitempty"`
+	Error         string     `json:"error,omitempty"`
+}
+
+func newOutcome(asset *prime.Balance) *Outcome {
+	return &Outcome{Symbol: asset.Symbol, Time: time.Now().UTC()}
+}
+
+func (o *Outcome) skip(reason SkipReason) *Outcome {
+	o.Action = ActionSkip
+	o.SkipReason = reason
+	return o
+}
+
+// plan marks an order or conversion that a dry run would submit.
+func (o *Outcome) plan() *Outcome {
+	o.Action = ActionPlanned
+	return o
+}
+
+func (o *Outcome) fail(err error) *Outcome {
+	o.Action = ActionFailed
+	o.Error = err.Error()
+	return o
+}
+
+// changed returns true if the outcome differs from the previous one
+// in a way that is worth logging.
+func (o *Outcome) changed(previous *Outcome) bool {
+	return previous == nil ||
+		previous.Action != o.Action ||
+		previous.SkipReason != o.SkipReason
+}
+
+// apply sets the decision fields on the audit record.
+func (o *Outcome) apply(rec *audit.Record) {
+	rec.Action = string(o.Action)
+	rec.SkipReason = string(o.SkipReason)
+	rec.OrderType = o.OrderType
+	rec.ClientOrderId = o.ClientOrderId
+	rec.OrderId = o.OrderId
+	rec.ActivityId = o.ActivityId
+	rec.Error = o.Error
+}
@@ -0,0 +1,118 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/coinbase-samples/prime-liquidator-go/audit"
+)
+
+// PlanEntry is what the next pass would do with an asset, from the
+// decision inputs and the computed order.
+type PlanEntry struct {
+	Symbol     string     `json:"symbol"`
+	Balance    string     `json:"balance"`
+	Holds      string     `json:"holds"`
+	Price      string     `json:"price,omitempty"`
+	OrderSize  string     `json:"order_size,omitempty"`
+	Notional   string     `json:"notional,omitempty"`
+	Strategy   string     `json:"strategy,omitempty"`
+	LimitPrice string     `json:"limit_price,omitempty"`
+	Duration   string     `json:"duration,omitempty"`
+	Action     Action     `json:"action"`
+	SkipReason SkipReason `json:"skip_reason,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// Plan makes a dry run over the trading balances and returns what would
+// be sold or converted, sorted by symbol. The decision logic is the same
+// as a pass, but nothing is submitted or written to the audit log.
+func (l *Liquidator) Plan() ([]*PlanEntry, error) {
+
+	if err := l.describeCurrentState(); err != nil {
+		return nil, err
+	}
+
+	planned := &planStore{}
+
+	store := l.audit
+	l.audit = planned
+	l.dryRun = true
+
+	defer func() {
+		l.audit = store
+		l.dryRun = false
+	}()
+
+	// Errors are reported on the entries and do not halt or back off the
+	// liquidator
+	for _, asset := range l.currentBalances() {
+		l.processAsset(asset, false)
+	}
+
+	entries := make([]*PlanEntry, 0, len(planned.records))
+	for _, rec := range planned.records {
+		entries = append(entries, newPlanEntry(rec))
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Symbol < entries[j].Symbol })
+
+	return entries, nil
+}
+
+func newPlanEntry(rec *audit.Record) *PlanEntry {
+
+	e := &PlanEntry{
+		Symbol:     rec.Symbol,
+		Balance:    rec.Amount,
+		Holds:      rec.Holds,
+		Price:      rec.Price,
+		OrderSize:  rec.OrderSize,
+		Notional:   rec.Value,
+		Strategy:   rec.OrderType,
+		LimitPrice: rec.LimitPrice,
+		Duration:   rec.Duration,
+		Action:     Action(rec.Action),
+		SkipReason: SkipReason(rec.SkipReason),
+		Error:      rec.Error,
+	}
+
+	if len(rec.Destination) > 0 {
+		e.Strategy = fmt.Sprintf("CONVERSION->%s", strings.ToUpper(rec.Destination))
+	}
+
+	return e
+}
+
+// planStore keeps the decision records of a dry run in memory.
+type planStore struct {
+	lock    sync.Mutex
+	records []*audit.Record
+}
+
+func (s *planStore) Append(rec *audit.Record) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.records = append(s.records, rec)
+	return nil
+}
+
+func (s *planStore) Close() error { return nil }
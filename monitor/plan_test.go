@@ -0,0 +1,173 @@
+/**
+ * Copyright 2023-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/coinbase-samples/prime-liquidator-go/monitor/caller"
+	prime "github.com/coinbase-samples/prime-sdk-go"
+	"github.com/shopspring/decimal"
+)
+
+func TestPlan(t *testing.T) {
+
+	call := &commandCaller{
+		catalogueCaller: catalogueCaller{
+			wallets: caller.WalletLookup{
+				"USDC": &prime.Wallet{Id: "usdc-trading", Symbol: "USDC"},
+				"USD":  &prime.Wallet{Id: "usd-trading", Symbol: "USD"},
+			},
+			products: caller.ProductLookup{
+				"ETH-USD": &prime.Product{Id: "ETH-USD", QuoteIncrement: "0.01", QuoteMinSize: "1"},
+				"SOL-USD": &prime.Product{Id: "SOL-USD", QuoteIncrement: "0.01", QuoteMinSize: "1"},
+			},
+		},
+		balances: []*prime.Balance{
+			{Symbol: "usdc", Amount: "500", Holds: "0"},
+			{Symbol: "eth", Amount: "10", Holds: "2"},
+			{Symbol: "usd", Amount: "1000", Holds: "0"},
+			{Symbol: "sol", Amount: "3", Holds: "0"},
+			{Symbol: "btc", Amount: "1", Holds: "0"},
+		},
+		prices: map[string]decimal.Decimal{
+			"ETH-USD": decimal.NewFromInt(2000),
+			"SOL-USD": decimal.NewFromInt(100),
+			"BTC-USD": decimal.NewFromInt(60000),
+		},
+	}
+
+	l := newCommandLiquidator(t, call)
+
+	entries, err := l.Plan()
+	if err != nil {
+		t.Fatalf("cannot plan: %v", err)
+	}
+
+	if call.submitted != 0 {
+		t.Errorf("expected no submissions - received: %d", call.submitted)
+	}
+
+	if records := l.audit.(*recorder).records; len(records) != 0 {
+		t.Errorf("expected no audit records - received: %d", len(records))
+	}
+
+	if l.dryRun {
+		t.Errorf("expected the dry run to be reset")
+	}
+
+	expected := []PlanEntry{
+		{Symbol: "btc", Balance: "1", Holds: "0", Price: "60000", Action: ActionFailed},
+		{
+			Symbol:     "eth",
+			Balance:    "10",
+			Holds:      "2",
+			Price:      "2000",
+			OrderSize:  "8",
+			Notional:   "16000",
+			Strategy:   prime.OrderTypeTwap,
+			LimitPrice: "1900",
+			Action:     ActionPlanned,
+		},
+		{
+			Symbol:    "sol",
+			Balance:   "3",
+			Holds:     "0",
+			Price:     "100",
+			OrderSize: "3",
+			Notional:  "300",
+			Strategy:  prime.OrderTypeMarket,
+			Action:    ActionPlanned,
+		},
+		{Symbol: "usd", Balance: "1000", Holds: "0", Action: ActionSkip, SkipReason: SkipFiat},
+		{Symbol: "usdc", Balance: "500", Holds: "0", Strategy: "CONVERSION->USD", Action: ActionPlanned},
+	}
+
+	if len(entries) != len(expected) {
+		t.Fatalf("expected: %d entries - received: %d", len(expected), len(entries))
+	}
+
+	for i, e := range expected {
+		received := *entries[i]
+		received.Duration = ""
+		received.Error = ""
+		if received != e {
+			t.Errorf("expected: %+v - received: %+v", e, received)
+		}
+	}
+
+	if len(entries[0].Error) == 0 {
+		t.Errorf("expected an error for the unknown product - received: %+v", entries[0])
+	}
+
+	if len(entries[1].Duration) == 0 {
+		t.Errorf("expected a TWAP duration - received: %+v", entries[1])
+	}
+}
+
+func TestPlanErrors(t *testing.T) {
+
+	cases := []struct {
+		description string
+		err         error
+	}{
+		{
+			description: "TestPlanErrorsAuth",
+			err:         caller.ErrAuth,
+		},
+		{
+			description: "TestPlanErrorsRateLimited",
+			err:         caller.ErrRateLimited,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			call := &commandCaller{
+				catalogueCaller: catalogueCaller{
+					products: caller.ProductLookup{
+						"ETH-USD": &prime.Product{Id: "ETH-USD", QuoteIncrement: "0.01", QuoteMinSize: "1"},
+					},
+				},
+				balances:    []*prime.Balance{{Symbol: "eth", Amount: "10", Holds: "0"}},
+				priceErrors: map[string]error{"ETH-USD": fmt.Errorf("cannot get price - err: %w", tt.err)},
+			}
+
+			l := newCommandLiquidator(t, call)
+			l.running.Store(true)
+
+			entries, err := l.Plan()
+			if err != nil {
+				t.Fatalf("test: %s - cannot plan: %v", tt.description, err)
+			}
+
+			if len(entries) != 1 || entries[0].Action != ActionFailed || len(entries[0].Error) == 0 {
+				t.Fatalf("test: %s - expected a failed entry with the error - received: %+v", tt.description, entries)
+			}
+
+			if !l.running.Load() || l.rateLimited.Load() {
+				t.Errorf("test: %s - expected the liquidator not to halt or back off", tt.description)
+			}
+
+			if events := l.notifier.(*recorder).events; len(events) != 0 {
+				t.Errorf("test: %s - expected no events - received: %d", tt.description, len(events))
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"time"
+
+	prime "github.com/coinbase-samples/prime-sdk-go"
+)
+
+// poller adapts the time between passes to the portfolio activity. The
+// interval drops to the minimum while balances are changing or orders
+// are working and doubles after each idle pass up to a limit. It is only
+// used by the monitor loop.
+type poller struct {
+	min      time.Duration
+	base     time.Duration
+	interval time.Duration
+	balances map[string]string
+}
+
+// newPoller returns a poller that starts at the base interval. The
+// minimum is raised to the base if it is not positive or is greater
+// than the base.
+func newPoller(min, base time.Duration) *poller {
+
+	if min <= 0 || min > base {
+		min = base
+	}
+
+	return &poller{min: min, base: base, interval: base}
+}
+
+// next returns the time to wait before the next pass. The limit caps
+// the idle interval and is never less than the base interval.
+func (p *poller) next(balances []*prime.Balance, working bool, limit time.Duration) time.Duration {
+
+	if limit < p.base {
+		limit = p.base
+	}
+
+	if p.changed(balances) || working {
+		p.interval = p.min
+	} else if p.interval *= 2; p.interval > limit {
+		p.interval = limit
+	}
+
+	return p.interval
+}
+
+// changed records the balances and returns true if any amount or hold
+// differs from the previous pass. The first pass is not a change.
+func (p *poller) changed(balances []*prime.Balance) bool {
+
+	current := make(map[string]string, len(balances))
+	for _, b := range balances {
+		current[b.Symbol] = b.Amount + "/" + b.Holds
+	}
+
+	previous := p.balances
+	p.balances = current
+
+	if previous == nil {
+		return false
+	}
+
+	if len(previous) != len(current) {
+		return true
+	}
+
+	for symbol, v := range current {
+		if previous[symbol] != v {
+			return true
+		}
+	}
+
+	return false
+}
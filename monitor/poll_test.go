@@ -0,0 +1,56 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	prime "github.com/coinbase-samples/prime-sdk-go"
+)
+
+func TestPollerNext(t *testing.T) {
+
+	p := newPoller(2*time.Second, 5*time.Second)
+
+	idle := []*prime.Balance{{Symbol: "btc", Amount: "1", Holds: "0"}}
+	deposit := []*prime.Balance{{Symbol: "btc", Amount: "2", Holds: "0"}}
+
+	cases := []struct {
+		description string
+		balances    []*prime.Balance
+		working     bool
+		limit       time.Duration
+		expected    time.Duration
+	}{
+		{description: "TestPollerNextFirstPass", balances: idle, limit: 30 * time.Second, expected: 10 * time.Second},
+		{description: "TestPollerNextIdle", balances: idle, limit: 30 * time.Second, expected: 20 * time.Second},
+		{description: "TestPollerNextIdleLimit", balances: idle, limit: 30 * time.Second, expected: 30 * time.Second},
+		{description: "TestPollerNextBalanceChanged", balances: deposit, limit: 30 * time.Second, expected: 2 * time.Second},
+		{description: "TestPollerNextOrdersWorking", balances: deposit, working: true, limit: 30 * time.Second, expected: 2 * time.Second},
+		{description: "TestPollerNextIdleAgain", balances: deposit, limit: 30 * time.Second, expected: 4 * time.Second},
+		{description: "TestPollerNextLimitBelowBase", balances: deposit, limit: time.Second, expected: 5 * time.Second},
+		{description: "TestPollerNextAssetAdded", balances: append(deposit, &prime.Balance{Symbol: "eth"}), limit: 30 * time.Second, expected: 2 * time.Second},
+	}
+
+	for _, tt := range cases {
+		result := p.next(tt.balances, tt.working, tt.limit)
+		if result != tt.expected {
+			t.Errorf("test: %s - expected: %v - received: %v", tt.description, tt.expected, result)
+		}
+	}
+}
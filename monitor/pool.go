@@ -0,0 +1,80 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"sync"
+	"sync/atomic"
+
+	prime "github.com/coinbase-samples/prime-sdk-go"
+	"go.uber.org/zap"
+)
+
+// processBalances processes the balances with a bounded pool of workers
+// and returns true if the pass completed. Once an error stops the pass,
+// the assets that have not started are not processed. Requests made by
+// the workers share the caller's rate limiters, so adding workers does
+// not increase the request rate beyond the configured limits.
+func (l *Liquidator) processBalances(balances []*prime.Balance, sweep bool) bool {
+
+	workers := l.config.AssetConcurrency()
+	if workers > len(balances) {
+		workers = len(balances)
+	}
+
+	var stopped atomic.Bool
+	var wg sync.WaitGroup
+
+	assets := make(chan *prime.Balance)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for asset := range assets {
+				if stopped.Load() {
+					continue
+				}
+
+				o, err := l.processAsset(asset, sweep)
+				l.recordOutcome(o)
+				if err != nil {
+					zap.L().Error("unable to process assets", zap.String("symbol", asset.Symbol), zap.Error(err))
+					if l.handleError(err) {
+						stopped.Store(true)
+					}
+				}
+			}
+		}()
+	}
+
+	for _, asset := range balances {
+		if !l.leading() {
+			stopped.Store(true)
+		}
+		if stopped.Load() {
+			break
+		}
+		assets <- asset
+	}
+
+	close(assets)
+
+	wg.Wait()
+
+	return !stopped.Load()
+}
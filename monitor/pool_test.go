@@ -0,0 +1,86 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/coinbase-samples/prime-liquidator-go/audit"
+	"github.com/coinbase-samples/prime-liquidator-go/config"
+	prime "github.com/coinbase-samples/prime-sdk-go"
+)
+
+func TestProcessBalances(t *testing.T) {
+
+	cases := []struct {
+		description string
+		concurrency string
+		assets      int
+	}{
+		{description: "TestProcessBalancesSequential", concurrency: "1", assets: 5},
+		{description: "TestProcessBalancesConcurrent", concurrency: "4", assets: 25},
+		{description: "TestProcessBalancesMoreWorkersThanAssets", concurrency: "8", assets: 3},
+		{description: "TestProcessBalancesNoAssets", concurrency: "4", assets: 0},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			c := &config.AppConfig{AssetConcurrencyCount: tt.concurrency}
+
+			store, err := audit.NewStore(c)
+			if err != nil {
+				t.Fatalf("cannot create audit store: %v", err)
+			}
+
+			s, err := newSchedule("", "", "")
+			if err != nil {
+				t.Fatalf("cannot create schedule: %v", err)
+			}
+
+			l := &Liquidator{
+				config:   c,
+				audit:    store,
+				schedule: s,
+				outcomes: make(map[string]*Outcome),
+			}
+
+			var balances []*prime.Balance
+			for i := 0; i < tt.assets; i++ {
+				balances = append(balances, &prime.Balance{Symbol: fmt.Sprintf("usd%d", i), Amount: "0"})
+			}
+
+			// The symbols are not fiat, so each asset is skipped for
+			// having a zero amount.
+			if !l.processBalances(balances, false) {
+				t.Fatalf("test: %s - expected pass to complete", tt.description)
+			}
+
+			outcomes := l.Outcomes()
+			if len(outcomes) != tt.assets {
+				t.Fatalf("test: %s - expected: %d - received: %d", tt.description, tt.assets, len(outcomes))
+			}
+
+			for _, o := range outcomes {
+				if o.SkipReason != SkipZeroAmount {
+					t.Errorf("test: %s - expected: %s - received: %s", tt.description, SkipZeroAmount, o.SkipReason)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/config"
+	"github.com/coinbase-samples/prime-liquidator-go/notify"
+	prime "github.com/coinbase-samples/prime-sdk-go"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// proceedsDigits is the number of decimal places that swept amounts are
+// rounded down to.
+const proceedsDigits = 2
+
+const transactionDone = "TRANSACTION_DONE"
+
+// Transactions in these states did not move funds, so they do not count
+// toward the daily cap.
+var transactionNotSwept = map[string]bool{
+	"TRANSACTION_FAILED":    true,
+	"TRANSACTION_CANCELLED": true,
+	"TRANSACTION_REJECTED":  true,
+	"TRANSACTION_EXPIRED":   true,
+}
+
+// Transactions in these states have finished. Others may still be waiting
+// for approval or processing.
+var transactionFinished = map[string]bool{
+	transactionDone:         true,
+	"TRANSACTION_IMPORTED":  true,
+	"TRANSACTION_FAILED":    true,
+	"TRANSACTION_CANCELLED": true,
+	"TRANSACTION_REJECTED":  true,
+	"TRANSACTION_EXPIRED":   true,
+}
+
+// proceedsSweep moves the balance of the trading wallet above the
+// threshold to another wallet in the portfolio, a payment method, or a
+// whitelisted address. Each sweep is limited to the max amount and the
+// total swept on a UTC day is limited to the daily cap. A zero cap is
+// not applied. The total swept is loaded from the wallet transactions in
+// Prime on the first sweep of the day, after a restart, and after a
+// leadership change. Other sweep state is only accessed by the monitor
+// loop.
+type proceedsSweep struct {
+	symbol          string
+	threshold       decimal.Decimal
+	maxAmount       decimal.Decimal
+	dailyCap        decimal.Decimal
+	walletId        string
+	paymentMethodId string
+	address         string
+	interval        time.Duration
+	last            time.Time
+	day             string
+	swept           decimal.Decimal
+	capNotified     bool
+	loaded          atomic.Bool
+}
+
+// newProceedsSweep returns nil if the threshold is not set. Exactly one
+// destination must be set.
+func newProceedsSweep(config *config.AppConfig) (*proceedsSweep, error) {
+
+	if len(config.ProceedsSweepThresholdValue) == 0 {
+		return nil, nil
+	}
+
+	p := &proceedsSweep{
+		symbol:          strings.ToLower(config.ProceedsSweepSymbol),
+		threshold:       config.ProceedsSweepThreshold(),
+		maxAmount:       config.ProceedsSweepMaxAmount(),
+		dailyCap:        config.ProceedsSweepDailyCap(),
+		walletId:        config.ProceedsSweepWalletId,
+		paymentMethodId: config.ProceedsSweepPaymentMethod,
+		address:         config.ProceedsSweepAddress,
+		interval:        config.ProceedsSweepInterval(),
+	}
+
+	if len(p.symbol) == 0 {
+		p.symbol = strings.ToLower(config.FiatCurrencySymbol)
+	}
+
+	var destinations int
+	for _, v := range []string{p.walletId, p.paymentMethodId, p.address} {
+		if len(v) > 0 {
+			destinations++
+		}
+	}
+
+	if destinations != 1 {
+		return nil, errors.New("proceeds sweep requires one of a wallet id, payment method id, or address")
+	}
+
+	if p.threshold.IsNegative() || p.maxAmount.IsNegative() || p.dailyCap.IsNegative() {
+		return nil, errors.New("proceeds sweep threshold and caps cannot be negative")
+	}
+
+	return p, nil
+}
+
+func (p *proceedsSweep) due(now time.Time) bool {
+	return now.Sub(p.last) >= p.interval
+}
+
+func (p *proceedsSweep) destination() string {
+	if len(p.walletId) > 0 {
+		return p.walletId
+	}
+	if len(p.paymentMethodId) > 0 {
+		return p.paymentMethodId
+	}
+	return p.address
+}
+
+// amount returns the amount to sweep from the available balance and
+// whether it was limited by the daily cap.
+func (p *proceedsSweep) amount(available decimal.Decimal, now time.Time) (amount decimal.Decimal, capped bool) {
+
+	if day := now.UTC().Format(dateLayout); day != p.day {
+		p.day = day
+		p.swept = decimal.Zero
+		p.capNotified = false
+	}
+
+	amount = available.Sub(p.threshold)
+
+	if p.maxAmount.IsPositive() && amount.GreaterThan(p.maxAmount) {
+		amount = p.maxAmount
+	}
+
+	if p.dailyCap.IsPositive() {
+		if remaining := p.dailyCap.Sub(p.swept); amount.GreaterThanOrEqual(remaining) {
+			amount = remaining
+			capped = true
+		}
+	}
+
+	amount = amount.RoundFloor(proceedsDigits)
+	return
+}
+
+// sweepProceeds sweeps the trading wallet balance above the threshold
+// once all tracked orders and conversions have settled. No sweep is
+// submitted while a previous sweep is waiting for approval or processing.
+func (l *Liquidator) sweepProceeds(now time.Time) error {
+
+	p := l.proceeds
+
+	if p == nil || !p.due(now) || l.working() {
+		return nil
+	}
+
+	p.last = now
+
+	wallet, err := l.catalogue.wallet(p.symbol)
+	if err != nil {
+		return err
+	}
+
+	if wallet == nil {
+		return fmt.Errorf("proceeds wallet not found: %s", p.symbol)
+	}
+
+	if l.transferPending(wallet.Id) {
+		return nil
+	}
+
+	balance, err := l.call.PrimeDescribeWalletBalance(wallet.Id)
+	if err != nil {
+		return err
+	}
+
+	if balance == nil {
+		return nil
+	}
+
+	available := balance.WithdrawableAmount
+	if len(available) == 0 {
+		available = balance.Amount
+	}
+
+	availableNum, err := prime.Balance{Symbol: wallet.Symbol, Amount: available}.AmountNum()
+	if err != nil {
+		return err
+	}
+
+	if !p.loaded.Load() || p.day != now.UTC().Format(dateLayout) {
+		if pending, err := l.loadProceedsSwept(wallet, now); err != nil || pending {
+			return err
+		}
+	}
+
+	amount, capped := p.amount(availableNum, now)
+
+	if amount.IsPositive() {
+		if err := l.submitProceedsSweep(wallet, amount); err != nil {
+			return err
+		}
+		p.swept = p.swept.Add(amount)
+	}
+
+	if capped && !p.capNotified {
+		p.capNotified = true
+
+		zap.L().Warn("proceeds sweep daily cap reached", zap.String("symbol", p.symbol), zap.String("swept", p.swept.String()))
+
+		e := notify.NewEvent(notify.EventProceedsCapReached)
+		e.Symbol = p.symbol
+		e.Value = p.swept.String()
+		e.Destination = p.destination()
+		l.notifier.Notify(e)
+	}
+
+	return nil
+}
+
+// loadProceedsSwept sets the total swept today from the transactions of
+// the wallet to the sweep destination since the start of the UTC day.
+// Sweeps are otherwise only tracked in memory, so without this a restart
+// would reset the daily cap. Returns true if a sweep submitted before the
+// restart is still waiting for approval or processing, in which case the
+// total is loaded again on the next sweep.
+func (l *Liquidator) loadProceedsSwept(wallet *prime.Wallet, now time.Time) (pending bool, err error) {
+
+	p := l.proceeds
+
+	start := now.UTC().Truncate(24 * time.Hour)
+
+	transactions, err := l.call.PrimeDescribeWalletTransactions(wallet.Id, start)
+	if err != nil {
+		return false, fmt.Errorf("cannot load proceeds swept today: %w", err)
+	}
+
+	swept := decimal.Zero
+
+	for _, tx := range transactions {
+
+		if tx.TransferTo == nil || tx.TransferTo.Value != p.destination() || transactionNotSwept[tx.Status] {
+			continue
+		}
+
+		if !transactionFinished[tx.Status] {
+			pending = true
+		}
+
+		amount, err := decimal.NewFromString(tx.Amount)
+		if err != nil {
+			return false, fmt.Errorf("invalid transaction amount: %s - transaction id: %s - err: %w", tx.Amount, tx.Id, err)
+		}
+
+		swept = swept.Add(amount.Abs())
+	}
+
+	if pending {
+		zap.L().Info("proceeds sweep pending", zap.String("symbol", p.symbol), zap.String("walletId", wallet.Id))
+		return true, nil
+	}
+
+	if day := now.UTC().Format(dateLayout); day != p.day {
+		p.day = day
+		p.capNotified = false
+	}
+
+	p.swept = swept
+	p.loaded.Store(true)
+
+	zap.L().Info("proceeds swept today loaded", zap.String("symbol", p.symbol), zap.String("swept", swept.String()))
+
+	return false, nil
+}
+
+func (l *Liquidator) submitProceedsSweep(wallet *prime.Wallet, amount decimal.Decimal) error {
+
+	p := l.proceeds
+
+	e := notify.NewEvent(notify.EventTransferSubmitted)
+	e.Symbol = wallet.Symbol
+	e.Size = amount.String()
+	e.Destination = p.destination()
+
+	var activityId, approvalUrl string
+
+	if len(p.walletId) > 0 {
+		response, err := l.call.PrimeCreateWalletTransfer(wallet, &prime.Wallet{Id: p.walletId, Symbol: wallet.Symbol}, amount)
+		if err != nil {
+			e.Type = notify.EventTransferFailed
+			l.notifier.Notify(e.WithError(err))
+			return err
+		}
+		activityId, approvalUrl = response.ActivityId, response.ApprovalUrl
+	} else {
+		response, err := l.call.PrimeCreateWalletWithdrawal(wallet, amount, p.paymentMethodId, p.address)
+		if err != nil {
+			e.Type = notify.EventTransferFailed
+			l.notifier.Notify(e.WithError(err))
+			return err
+		}
+		activityId, approvalUrl = response.ActivityId, response.ApprovalUrl
+	}
+
+	transferCounts.Add("submitted", 1)
+
+	l.trackTransfer(activityId, wallet.Symbol, amount.String(), wallet.Id, p.destination())
+
+	e.ActivityId = activityId
+	e.ApprovalUrl = approvalUrl
+	l.notifier.Notify(e)
+
+	return nil
+}
@@ -0,0 +1,295 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/config"
+	"github.com/coinbase-samples/prime-liquidator-go/monitor/caller"
+	"github.com/coinbase-samples/prime-liquidator-go/notify"
+	prime "github.com/coinbase-samples/prime-sdk-go"
+	"github.com/shopspring/decimal"
+)
+
+func TestProceedsSweepAmount(t *testing.T) {
+
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		description string
+		maxAmount   int64
+		dailyCap    int64
+		swept       int64
+		available   string
+		expected    string
+		capped      bool
+	}{
+		{
+			description: "TestProceedsSweepAmountAboveThreshold",
+			available:   "1500.555",
+			expected:    "500.55",
+		},
+		{
+			description: "TestProceedsSweepAmountBelowThreshold",
+			available:   "900",
+			expected:    "-100",
+		},
+		{
+			description: "TestProceedsSweepAmountMaxAmount",
+			maxAmount:   200,
+			available:   "1500",
+			expected:    "200",
+		},
+		{
+			description: "TestProceedsSweepAmountDailyCap",
+			dailyCap:    1000,
+			swept:       800,
+			available:   "1500",
+			expected:    "200",
+			capped:      true,
+		},
+		{
+			description: "TestProceedsSweepAmountDailyCapReached",
+			dailyCap:    1000,
+			swept:       1000,
+			available:   "1500",
+			expected:    "0",
+			capped:      true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			p := &proceedsSweep{
+				threshold: decimal.NewFromInt(1000),
+				maxAmount: decimal.NewFromInt(tt.maxAmount),
+				dailyCap:  decimal.NewFromInt(tt.dailyCap),
+				day:       now.Format(dateLayout),
+				swept:     decimal.NewFromInt(tt.swept),
+			}
+
+			amount, capped := p.amount(decimal.RequireFromString(tt.available), now)
+
+			if !amount.Equal(decimal.RequireFromString(tt.expected)) {
+				t.Errorf("test: %s - expected: %s - received: %s", tt.description, tt.expected, amount)
+			}
+
+			if capped != tt.capped {
+				t.Errorf("test: %s - expected capped: %t - received: %t", tt.description, tt.capped, capped)
+			}
+		})
+	}
+}
+
+func TestNewProceedsSweep(t *testing.T) {
+
+	cases := []struct {
+		description string
+		config      config.AppConfig
+		enabled     bool
+		expectError bool
+	}{
+		{
+			description: "TestNewProceedsSweepDisabled",
+		},
+		{
+			description: "TestNewProceedsSweepWallet",
+			config:      proceedsConfig("wallet-1", ""),
+			enabled:     true,
+		},
+		{
+			description: "TestNewProceedsSweepNoDestination",
+			config:      proceedsConfig("", ""),
+			expectError: true,
+		},
+		{
+			description: "TestNewProceedsSweepTwoDestinations",
+			config:      proceedsConfig("wallet-1", "0xabc"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			p, err := newProceedsSweep(&tt.config)
+
+			if tt.expectError != (err != nil) {
+				t.Fatalf("test: %s - expected error: %t - received: %v", tt.description, tt.expectError, err)
+			}
+
+			if tt.enabled != (p != nil) {
+				t.Errorf("test: %s - expected enabled: %t - received: %t", tt.description, tt.enabled, p != nil)
+			}
+		})
+	}
+}
+
+func TestSweepProceeds(t *testing.T) {
+
+	call := &vaultCaller{
+		catalogueCaller: catalogueCaller{
+			wallets: caller.WalletLookup{"USD": &prime.Wallet{Id: "usd-trading", Symbol: "USD"}},
+		},
+		balances: map[string]*prime.Balance{
+			"usd-trading": {Symbol: "USD", Amount: "5000"},
+		},
+	}
+
+	r := &recorder{}
+
+	cfg := proceedsConfig("usd-treasury", "")
+	cfg.ProceedsSweepDailyCapValue = "2500"
+
+	l := &Liquidator{
+		config:      &cfg,
+		call:        call,
+		catalogue:   newCatalogue(call, time.Hour, time.Hour),
+		audit:       r,
+		notifier:    r,
+		orders:      make(map[string]*trackedOrder),
+		conversions: make(map[string]*trackedConversion),
+		transfers:   make(map[string]*trackedTransfer),
+	}
+
+	var err error
+	if l.proceeds, err = newProceedsSweep(l.config); err != nil {
+		t.Fatalf("cannot create proceeds sweep: %v", err)
+	}
+
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	if err := l.sweepProceeds(now); err != nil {
+		t.Fatalf("cannot sweep proceeds: %v", err)
+	}
+
+	if len(call.transfers) != 1 || call.transfers[0] != "usd-trading->usd-treasury:2000" {
+		t.Fatalf("expected a transfer above the threshold - received: %v", call.transfers)
+	}
+
+	// The previous sweep is pending, so the wallet is not swept
+	if err := l.sweepProceeds(now.Add(2 * time.Hour)); err != nil {
+		t.Fatalf("cannot sweep proceeds: %v", err)
+	}
+
+	l.untrackTransfer("activity-usd-trading")
+
+	if err := l.sweepProceeds(now.Add(4 * time.Hour)); err != nil {
+		t.Fatalf("cannot sweep proceeds: %v", err)
+	}
+
+	if len(call.transfers) != 2 || call.transfers[1] != "usd-trading->usd-treasury:500" {
+		t.Errorf("expected the sweep to be capped - received: %v", call.transfers)
+	}
+
+	var capReached int
+	for _, e := range r.events {
+		if e.Type == notify.EventProceedsCapReached {
+			capReached++
+		}
+	}
+
+	if capReached != 1 {
+		t.Errorf("expected one cap reached event - received: %d", capReached)
+	}
+}
+
+func TestSweepProceedsAfterRestart(t *testing.T) {
+
+	treasury := &prime.Transfer{Type: "WALLET", Value: "usd-treasury"}
+
+	call := &vaultCaller{
+		catalogueCaller: catalogueCaller{
+			wallets: caller.WalletLookup{"USD": &prime.Wallet{Id: "usd-trading", Symbol: "USD"}},
+		},
+		balances: map[string]*prime.Balance{
+			"usd-trading": {Symbol: "USD", Amount: "5000"},
+		},
+		// Swept before the restart, earlier in the day. Failed transfers,
+		// deposits, and transfers to other destinations are not counted.
+		walletTxs: map[string][]*prime.Transaction{
+			"usd-trading": {
+				{Id: "tx-1", Status: "TRANSACTION_DONE", Amount: "1500", TransferTo: treasury},
+				{Id: "tx-2", Status: "TRANSACTION_REQUESTED", Amount: "600", TransferTo: treasury},
+				{Id: "tx-3", Status: "TRANSACTION_FAILED", Amount: "900", TransferTo: treasury},
+				{Id: "tx-4", Status: "TRANSACTION_DONE", Amount: "700", TransferTo: &prime.Transfer{Type: "WALLET", Value: "usd-other"}},
+				{Id: "tx-5", Status: "TRANSACTION_DONE", Type: "DEPOSIT", Amount: "5000"},
+			},
+		},
+	}
+
+	r := &recorder{}
+
+	cfg := proceedsConfig("usd-treasury", "")
+	cfg.ProceedsSweepDailyCapValue = "2500"
+
+	l := &Liquidator{
+		config:      &cfg,
+		call:        call,
+		catalogue:   newCatalogue(call, time.Hour, time.Hour),
+		audit:       r,
+		notifier:    r,
+		orders:      make(map[string]*trackedOrder),
+		conversions: make(map[string]*trackedConversion),
+		transfers:   make(map[string]*trackedTransfer),
+	}
+
+	var err error
+	if l.proceeds, err = newProceedsSweep(l.config); err != nil {
+		t.Fatalf("cannot create proceeds sweep: %v", err)
+	}
+
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	// The sweep submitted before the restart is still waiting for approval
+	if err := l.sweepProceeds(now); err != nil {
+		t.Fatalf("cannot sweep proceeds: %v", err)
+	}
+
+	if len(call.transfers) != 0 {
+		t.Fatalf("expected no transfer while the previous sweep is pending - received: %v", call.transfers)
+	}
+
+	call.walletTxs["usd-trading"][1].Status = "TRANSACTION_DONE"
+
+	if err := l.sweepProceeds(now.Add(2 * time.Hour)); err != nil {
+		t.Fatalf("cannot sweep proceeds: %v", err)
+	}
+
+	if len(call.transfers) != 1 || call.transfers[0] != "usd-trading->usd-treasury:400" {
+		t.Errorf("expected the sweep to be capped by the total swept before the restart - received: %v", call.transfers)
+	}
+
+	if !l.proceeds.swept.Equal(decimal.NewFromInt(2500)) {
+		t.Errorf("expected swept: 2500 - received: %s", l.proceeds.swept)
+	}
+}
+
+func proceedsConfig(walletId, address string) config.AppConfig {
+	return config.AppConfig{
+		FiatCurrencySymbol:          "usd",
+		ProceedsSweepThresholdValue: "3000",
+		ProceedsSweepWalletId:       walletId,
+		ProceedsSweepAddress:        address,
+		ProceedsSweepMaxValue:       "0",
+		ProceedsSweepDailyCapValue:  "0",
+		ProceedsSweepIntervalInMins: "60",
+	}
+}
@@ -0,0 +1,157 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// defaultConversionDigits is the number of decimal places that conversion
+// amounts are rounded down to when a route does not set them.
+const defaultConversionDigits = 2
+
+// conversionRoute converts the balance of the source wallet into the
+// destination wallet. Balances below the minimum are not converted.
+type conversionRoute struct {
+	source      string
+	destination string
+	minimum     decimal.Decimal
+	digits      int32
+}
+
+// conversionRoutes are keyed by the lowercase source symbol.
+type conversionRoutes map[string]*conversionRoute
+
+// newConversionRoutes returns a route to fiat for each convert symbol,
+// replaced or extended by the routes in the spec. Routes are comma
+// separated in the format "usdc->usd", optionally followed by a minimum
+// and rounding digits, e.g., "usd->usdc:min=1000:digits=2". Routes can
+// be chained, but cannot form a cycle, so balances are never converted
+// back and forth.
+func newConversionRoutes(convertSymbols []string, fiat, spec string) (routes conversionRoutes, err error) {
+
+	routes = make(conversionRoutes)
+
+	for _, s := range convertSymbols {
+		if s = strings.ToLower(strings.TrimSpace(s)); len(s) > 0 {
+			routes[s] = &conversionRoute{source: s, destination: strings.ToLower(fiat), digits: defaultConversionDigits}
+		}
+	}
+
+	configured := make(map[string]bool)
+
+	for _, v := range splitSpec(spec, ",") {
+
+		var r *conversionRoute
+		if r, err = parseConversionRoute(v); err != nil {
+			return
+		}
+
+		if configured[r.source] {
+			err = fmt.Errorf("duplicate conversion route source: %s", v)
+			return
+		}
+
+		configured[r.source] = true
+		routes[r.source] = r
+	}
+
+	// Each source has a single destination, so any chain longer than the
+	// number of routes revisits a route
+	for _, r := range routes {
+		next := r
+		for i := 0; next != nil; i++ {
+			if i > len(routes) {
+				err = fmt.Errorf("conversion routes form a cycle: %s->%s", r.source, r.destination)
+				return
+			}
+			next = routes[next.destination]
+		}
+	}
+
+	return
+}
+
+// lookup returns the route for the symbol or nil if it is not converted.
+func (r conversionRoutes) lookup(symbol string) *conversionRoute {
+	return r[strings.ToLower(symbol)]
+}
+
+func parseConversionRoute(v string) (r *conversionRoute, err error) {
+
+	parts := strings.Split(v, ":")
+
+	symbols := strings.Split(parts[0], "->")
+	if len(symbols) != 2 {
+		err = fmt.Errorf("invalid conversion route: %s", v)
+		return
+	}
+
+	r = &conversionRoute{
+		source:      strings.ToLower(strings.TrimSpace(symbols[0])),
+		destination: strings.ToLower(strings.TrimSpace(symbols[1])),
+		digits:      defaultConversionDigits,
+	}
+
+	if len(r.source) == 0 || len(r.destination) == 0 || r.source == r.destination {
+		err = fmt.Errorf("invalid conversion route: %s", v)
+		return
+	}
+
+	for _, option := range parts[1:] {
+
+		kv := strings.SplitN(strings.TrimSpace(option), "=", 2)
+		if len(kv) != 2 {
+			err = fmt.Errorf("invalid conversion route option: %s", v)
+			return
+		}
+
+		switch kv[0] {
+		case "min":
+			if r.minimum, err = decimal.NewFromString(kv[1]); err != nil {
+				err = fmt.Errorf("invalid conversion route minimum: %s - err: %w", v, err)
+				return
+			}
+			if r.minimum.IsNegative() {
+				err = fmt.Errorf("conversion route minimum cannot be negative: %s", v)
+				return
+			}
+
+		case "digits":
+			var digits int
+			if digits, err = strconv.Atoi(kv[1]); err != nil {
+				err = fmt.Errorf("invalid conversion route digits: %s - err: %w", v, err)
+				return
+			}
+			if digits < 0 {
+				err = fmt.Errorf("conversion route digits cannot be negative: %s", v)
+				return
+			}
+			r.digits = int32(digits)
+
+		default:
+			err = fmt.Errorf("unknown conversion route option: %s", v)
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,149 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestNewConversionRoutes(t *testing.T) {
+
+	cases := []struct {
+		description    string
+		convertSymbols []string
+		spec           string
+		symbol         string
+		destination    string
+		minimum        decimal.Decimal
+		digits         int32
+		expectError    bool
+	}{
+		{
+			description:    "TestNewConversionRoutesDefault",
+			convertSymbols: []string{"usdc"},
+			symbol:         "USDC",
+			destination:    "usd",
+			digits:         defaultConversionDigits,
+		},
+		{
+			description:    "TestNewConversionRoutesOverride",
+			convertSymbols: []string{"usdc"},
+			spec:           "usdc->usd:min=100:digits=4",
+			symbol:         "usdc",
+			destination:    "usd",
+			minimum:        decimal.NewFromInt(100),
+			digits:         4,
+		},
+		{
+			description: "TestNewConversionRoutesFiatToStablecoin",
+			spec:        "USD->USDC:min=1000, pyusd->usd",
+			symbol:      "usd",
+			destination: "usdc",
+			minimum:     decimal.NewFromInt(1000),
+			digits:      defaultConversionDigits,
+		},
+		{
+			description: "TestNewConversionRoutesNotConverted",
+			spec:        "pyusd->usd",
+			symbol:      "eth",
+		},
+		{
+			description:    "TestNewConversionRoutesCycle",
+			convertSymbols: []string{"usdc"},
+			spec:           "usd->usdc",
+			expectError:    true,
+		},
+		{
+			description: "TestNewConversionRoutesLongCycle",
+			spec:        "pyusd->usdc,usdc->usd,usd->usdc",
+			expectError: true,
+		},
+		{
+			description: "TestNewConversionRoutesDuplicate",
+			spec:        "pyusd->usd,pyusd->usdc",
+			expectError: true,
+		},
+		{
+			description: "TestNewConversionRoutesSameSymbol",
+			spec:        "usd->usd",
+			expectError: true,
+		},
+		{
+			description: "TestNewConversionRoutesInvalidFormat",
+			spec:        "usdc=usd",
+			expectError: true,
+		},
+		{
+			description: "TestNewConversionRoutesNegativeMinimum",
+			spec:        "usdc->usd:min=-1",
+			expectError: true,
+		},
+		{
+			description: "TestNewConversionRoutesInvalidDigits",
+			spec:        "usdc->usd:digits=two",
+			expectError: true,
+		},
+		{
+			description: "TestNewConversionRoutesUnknownOption",
+			spec:        "usdc->usd:max=10",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			routes, err := newConversionRoutes(tt.convertSymbols, "USD", tt.spec)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("test: %s - expected error - received: nil", tt.description)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("test: %s - cannot create routes: %v", tt.description, err)
+			}
+
+			route := routes.lookup(tt.symbol)
+			if len(tt.destination) == 0 {
+				if route != nil {
+					t.Errorf("test: %s - expected no route - received: %+v", tt.description, route)
+				}
+				return
+			}
+
+			if route == nil {
+				t.Fatalf("test: %s - expected route - received: nil", tt.description)
+			}
+
+			if route.destination != tt.destination {
+				t.Errorf("test: %s - expected: %s - received: %s", tt.description, tt.destination, route.destination)
+			}
+
+			if !route.minimum.Equal(tt.minimum) {
+				t.Errorf("test: %s - expected minimum: %s - received: %s", tt.description, tt.minimum, route.minimum)
+			}
+
+			if route.digits != tt.digits {
+				t.Errorf("test: %s - expected digits: %d - received: %d", tt.description, tt.digits, route.digits)
+			}
+		})
+	}
+}
@@ -0,0 +1,412 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	day        = 24 * time.Hour
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// tradingWindow is a daily UTC time range that applies to a set
+// of weekdays. The start is inclusive and the end is exclusive.
+type tradingWindow struct {
+	days  [7]bool
+	start time.Duration
+	end   time.Duration
+}
+
+func (w tradingWindow) contains(t time.Time) bool {
+	if !w.days[t.Weekday()] {
+		return false
+	}
+	offset := t.Sub(midnight(t))
+	return offset >= w.start && offset < w.end
+}
+
+// blackout is a UTC time range where no activity is permitted. The
+// start is inclusive and the end is exclusive.
+type blackout struct {
+	start time.Time
+	end   time.Time
+}
+
+func (b blackout) contains(t time.Time) bool {
+	return !t.Before(b.start) && t.Before(b.end)
+}
+
+// schedule gates when assets can be liquidated. If no windows are
+// configured, trading is permitted at any time outside of a blackout.
+// Asset windows replace the global windows for that asset.
+type schedule struct {
+	windows      []tradingWindow
+	assetWindows map[string][]tradingWindow
+	blackouts    []blackout
+}
+
+// newSchedule parses the trading window, asset trading window, and
+// blackout specifications.
+func newSchedule(windows, assetWindows, blackouts string) (s *schedule, err error) {
+
+	s = &schedule{assetWindows: make(map[string][]tradingWindow)}
+
+	if s.windows, err = parseTradingWindows(windows); err != nil {
+		return
+	}
+
+	for _, v := range splitSpec(assetWindows, ";") {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 || len(strings.TrimSpace(parts[0])) == 0 {
+			err = fmt.Errorf("invalid asset trading window: %s", v)
+			return
+		}
+
+		var w []tradingWindow
+		if w, err = parseTradingWindows(parts[1]); err != nil {
+			return
+		}
+
+		s.assetWindows[strings.ToLower(strings.TrimSpace(parts[0]))] = w
+	}
+
+	if s.blackouts, err = parseBlackouts(blackouts); err != nil {
+		return
+	}
+
+	return
+}
+
+// anyOpen returns true if at least one asset is permitted to trade
+// at the specified time.
+func (s *schedule) anyOpen(t time.Time) bool {
+	if s.inBlackout(t) {
+		return false
+	}
+
+	if windowsContain(s.windows, t) {
+		return true
+	}
+
+	for _, w := range s.assetWindows {
+		if windowsContain(w, t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isOpen returns true if the asset is permitted to trade at the
+// specified time.
+func (s *schedule) isOpen(symbol string, t time.Time) bool {
+	if s.inBlackout(t) {
+		return false
+	}
+	return windowsContain(s.windowsFor(symbol), t)
+}
+
+// clampExpiry returns the earliest of the requested expiry, the close
+// of the asset's current trading window, and the start of the next
+// blackout, so orders never extend outside of the schedule.
+func (s *schedule) clampExpiry(symbol string, start, expiry time.Time) time.Time {
+
+	start = start.UTC()
+	expiry = expiry.UTC()
+
+	for _, b := range s.blackouts {
+		if b.start.After(start) && b.start.Before(expiry) {
+			expiry = b.start
+		}
+	}
+
+	windows := s.windowsFor(symbol)
+	if len(windows) == 0 {
+		return expiry
+	}
+
+	closeTime := start
+	for closeTime.Before(expiry) {
+		next, ok := windowClose(windows, closeTime)
+		if !ok || !next.After(closeTime) {
+			break
+		}
+		closeTime = next
+	}
+
+	if closeTime.Before(expiry) {
+		return closeTime
+	}
+
+	return expiry
+}
+
+func (s *schedule) inBlackout(t time.Time) bool {
+	t = t.UTC()
+	for _, b := range s.blackouts {
+		if b.contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *schedule) windowsFor(symbol string) []tradingWindow {
+	if w, found := s.assetWindows[strings.ToLower(symbol)]; found {
+		return w
+	}
+	return s.windows
+}
+
+// windowsContain returns true if the time falls in one of the windows
+// or if there are no windows.
+func windowsContain(windows []tradingWindow, t time.Time) bool {
+	if len(windows) == 0 {
+		return true
+	}
+
+	t = t.UTC()
+	for _, w := range windows {
+		if w.contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// windowClose returns the latest close of the windows that contain
+// the specified time.
+func windowClose(windows []tradingWindow, t time.Time) (closeTime time.Time, found bool) {
+	for _, w := range windows {
+		if !w.contains(t) {
+			continue
+		}
+		c := midnight(t).Add(w.end)
+		if !found || c.After(closeTime) {
+			closeTime = c
+			found = true
+		}
+	}
+	return
+}
+
+func midnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// parseTradingWindows parses a comma separated list of windows in
+// the format "mon-fri 13:00-21:00" or "sat 10:00-14:00". The day
+// range may also be "daily".
+func parseTradingWindows(spec string) (windows []tradingWindow, err error) {
+
+	for _, v := range splitSpec(spec, ",") {
+
+		fields := strings.Fields(v)
+		if len(fields) != 2 {
+			err = fmt.Errorf("invalid trading window: %s", v)
+			return
+		}
+
+		var w tradingWindow
+
+		if w.days, err = parseDays(fields[0]); err != nil {
+			return
+		}
+
+		times := strings.Split(fields[1], "-")
+		if len(times) != 2 {
+			err = fmt.Errorf("invalid trading window times: %s", v)
+			return
+		}
+
+		if w.start, err = parseTimeOfDay(times[0]); err != nil {
+			return
+		}
+
+		if w.end, err = parseTimeOfDay(times[1]); err != nil {
+			return
+		}
+
+		if w.end <= w.start {
+			err = fmt.Errorf("trading window must end after it starts: %s", v)
+			return
+		}
+
+		windows = append(windows, w)
+	}
+
+	return
+}
+
+func parseDays(v string) (days [7]bool, err error) {
+
+	v = strings.ToLower(v)
+
+	if v == "daily" {
+		for i := range days {
+			days[i] = true
+		}
+		return
+	}
+
+	bounds := strings.Split(v, "-")
+	if len(bounds) > 2 {
+		err = fmt.Errorf("invalid trading window days: %s", v)
+		return
+	}
+
+	first, found := weekdays[bounds[0]]
+	if !found {
+		err = fmt.Errorf("invalid trading window day: %s", bounds[0])
+		return
+	}
+
+	last := first
+	if len(bounds) == 2 {
+		if last, found = weekdays[bounds[1]]; !found {
+			err = fmt.Errorf("invalid trading window day: %s", bounds[1])
+			return
+		}
+	}
+
+	for d := first; ; d = (d + 1) % 7 {
+		days[d] = true
+		if d == last {
+			break
+		}
+	}
+
+	return
+}
+
+// parseTimeOfDay parses HH:MM into an offset from midnight. The
+// value 24:00 is permitted to express the end of the day.
+func parseTimeOfDay(v string) (offset time.Duration, err error) {
+
+	parts := strings.Split(v, ":")
+	if len(parts) != 2 {
+		err = fmt.Errorf("invalid time of day: %s", v)
+		return
+	}
+
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		err = fmt.Errorf("invalid time of day: %s - err: %w", v, err)
+		return
+	}
+
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		err = fmt.Errorf("invalid time of day: %s - err: %w", v, err)
+		return
+	}
+
+	offset = time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute
+
+	if hours < 0 || minutes < 0 || minutes > 59 || offset > day {
+		err = fmt.Errorf("invalid time of day: %s", v)
+	}
+
+	return
+}
+
+// parseBlackouts parses a comma separated list of blackouts. Each
+// blackout is a UTC date (2024-12-25), an inclusive date range
+// (2024-12-24/2024-12-26), or an RFC 3339 time range
+// (2024-03-20T17:30:00Z/2024-03-20T19:00:00Z).
+func parseBlackouts(spec string) (blackouts []blackout, err error) {
+
+	for _, v := range splitSpec(spec, ",") {
+
+		bounds := strings.Split(v, "/")
+		if len(bounds) > 2 {
+			err = fmt.Errorf("invalid blackout: %s", v)
+			return
+		}
+
+		var b blackout
+
+		if d, dErr := time.Parse(dateLayout, bounds[0]); dErr == nil {
+
+			b.start = d
+			b.end = d.Add(day)
+
+			if len(bounds) == 2 {
+				var last time.Time
+				if last, err = time.Parse(dateLayout, bounds[1]); err != nil {
+					err = fmt.Errorf("invalid blackout end date: %s - err: %w", v, err)
+					return
+				}
+				b.end = last.Add(day)
+			}
+
+		} else {
+
+			if len(bounds) != 2 {
+				err = fmt.Errorf("invalid blackout: %s", v)
+				return
+			}
+
+			if b.start, err = time.Parse(time.RFC3339, bounds[0]); err != nil {
+				err = fmt.Errorf("invalid blackout start: %s - err: %w", v, err)
+				return
+			}
+
+			if b.end, err = time.Parse(time.RFC3339, bounds[1]); err != nil {
+				err = fmt.Errorf("invalid blackout end: %s - err: %w", v, err)
+				return
+			}
+
+			b.start = b.start.UTC()
+			b.end = b.end.UTC()
+		}
+
+		if !b.end.After(b.start) {
+			err = fmt.Errorf("blackout must end after it starts: %s", v)
+			return
+		}
+
+		blackouts = append(blackouts, b)
+	}
+
+	return
+}
+
+func splitSpec(spec, sep string) (values []string) {
+	for _, v := range strings.Split(spec, sep) {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			values = append(values, v)
+		}
+	}
+	return
+}
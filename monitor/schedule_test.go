@@ -0,0 +1,180 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleIsOpen(t *testing.T) {
+
+	s, err := newSchedule(
+		"mon-fri 13:00-21:00",
+		"eth=sat 10:00-12:00",
+		"2024-12-25,2024-03-20T17:30:00Z/2024-03-20T19:00:00Z",
+	)
+	if err != nil {
+		t.Fatalf("cannot create schedule: %v", err)
+	}
+
+	cases := []struct {
+		description string
+		symbol      string
+		time        string
+		expected    bool
+	}{
+		{
+			description: "TestScheduleIsOpenWeekdayInWindow",
+			symbol:      "btc",
+			time:        "2024-03-19T14:00:00Z",
+			expected:    true,
+		},
+		{
+			description: "TestScheduleIsOpenWeekdayBeforeWindow",
+			symbol:      "btc",
+			time:        "2024-03-19T12:59:59Z",
+			expected:    false,
+		},
+		{
+			description: "TestScheduleIsOpenWindowEndExclusive",
+			symbol:      "btc",
+			time:        "2024-03-19T21:00:00Z",
+			expected:    false,
+		},
+		{
+			description: "TestScheduleIsOpenWeekend",
+			symbol:      "btc",
+			time:        "2024-03-23T14:00:00Z",
+			expected:    false,
+		},
+		{
+			description: "TestScheduleIsOpenBlackoutDate",
+			symbol:      "btc",
+			time:        "2024-12-25T14:00:00Z",
+			expected:    false,
+		},
+		{
+			description: "TestScheduleIsOpenBlackoutRange",
+			symbol:      "btc",
+			time:        "2024-03-20T18:00:00Z",
+			expected:    false,
+		},
+		{
+			description: "TestScheduleIsOpenAssetWindow",
+			symbol:      "ETH",
+			time:        "2024-03-23T11:00:00Z",
+			expected:    true,
+		},
+		{
+			description: "TestScheduleIsOpenAssetWindowReplacesGlobal",
+			symbol:      "eth",
+			time:        "2024-03-19T14:00:00Z",
+			expected:    false,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			result := s.isOpen(tt.symbol, mustParseTime(t, tt.time))
+			if result != tt.expected {
+				t.Errorf("test: %s - expected: %t - received: %t", tt.description, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestScheduleClampExpiry(t *testing.T) {
+
+	s, err := newSchedule(
+		"mon-fri 13:00-21:00,sat 00:00-24:00,sun 00:00-24:00",
+		"",
+		"2024-03-21T14:00:00Z/2024-03-21T15:00:00Z",
+	)
+	if err != nil {
+		t.Fatalf("cannot create schedule: %v", err)
+	}
+
+	cases := []struct {
+		description string
+		start       string
+		expected    string
+	}{
+		{
+			description: "TestScheduleClampExpiryUnchanged",
+			start:       "2024-03-19T14:00:00Z",
+			expected:    "2024-03-19T15:00:00Z",
+		},
+		{
+			description: "TestScheduleClampExpiryWindowClose",
+			start:       "2024-03-19T20:30:00Z",
+			expected:    "2024-03-19T21:00:00Z",
+		},
+		{
+			description: "TestScheduleClampExpiryBlackout",
+			start:       "2024-03-21T13:15:00Z",
+			expected:    "2024-03-21T14:00:00Z",
+		},
+		{
+			description: "TestScheduleClampExpiryAdjacentWindows",
+			start:       "2024-03-23T23:30:00Z",
+			expected:    "2024-03-24T00:30:00Z",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			start := mustParseTime(t, tt.start)
+			result := s.clampExpiry("btc", start, start.Add(time.Hour))
+			if expected := mustParseTime(t, tt.expected); !result.Equal(expected) {
+				t.Errorf("test: %s - expected: %v - received: %v", tt.description, expected, result)
+			}
+		})
+	}
+}
+
+func TestNewScheduleInvalid(t *testing.T) {
+
+	cases := []struct {
+		description  string
+		windows      string
+		assetWindows string
+		blackouts    string
+	}{
+		{description: "TestNewScheduleInvalidDay", windows: "mon-xyz 13:00-21:00"},
+		{description: "TestNewScheduleInvalidTime", windows: "mon-fri 13:00-25:00"},
+		{description: "TestNewScheduleInvalidRange", windows: "mon-fri 21:00-13:00"},
+		{description: "TestNewScheduleInvalidAsset", assetWindows: "mon-fri 13:00-21:00"},
+		{description: "TestNewScheduleInvalidBlackout", blackouts: "2024-12-26/2024-12-25"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			if _, err := newSchedule(tt.windows, tt.assetWindows, tt.blackouts); err == nil {
+				t.Errorf("test: %s - expected error", tt.description)
+			}
+		})
+	}
+}
+
+func mustParseTime(t *testing.T, v string) time.Time {
+	r, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		t.Fatalf("cannot parse time: %s - err: %v", v, err)
+	}
+	return r
+}
@@ -0,0 +1,40 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+// Status is a point in time view of the liquidator.
+type Status struct {
+	Running       bool          `json:"running"`
+	Paused        bool          `json:"paused"`
+	Leader        bool          `json:"leader"`
+	OrderBreaker  BreakerStatus `json:"order_breaker"`
+	FeedConnected bool          `json:"feed_connected"`
+	Outcomes      []*Outcome    `json:"outcomes"`
+}
+
+// Status returns the current state of the liquidator and the most
+// recent outcome for each asset.
+func (l *Liquidator) Status() *Status {
+	return &Status{
+		Running:       l.running.Load(),
+		Paused:        l.paused.Load(),
+		Leader:        l.leading(),
+		OrderBreaker:  l.breaker.status(),
+		FeedConnected: l.feed != nil && l.feed.Connected(),
+		Outcomes:      l.Outcomes(),
+	}
+}
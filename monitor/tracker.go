@@ -0,0 +1,499 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/audit"
+	"github.com/coinbase-samples/prime-liquidator-go/monitor/caller"
+	"github.com/coinbase-samples/prime-liquidator-go/notify"
+	prime "github.com/coinbase-samples/prime-sdk-go"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// orderCompletionGrace is how long after an order's expiry it is
+// tracked before it is considered expired without a complete fill.
+const orderCompletionGrace = 5 * time.Minute
+
+const (
+	orderStatusFilled    = "filled"
+	orderStatusExpired   = "expired"
+	orderStatusCancelled = "cancelled"
+	orderStatusFailed    = "failed"
+)
+
+const orderStatusPrimeFilled = "FILLED"
+
+// orderFinishedStatuses are the Prime order statuses that finish an order
+// before it is completely filled.
+var orderFinishedStatuses = []string{"CANCELLED", "EXPIRED", "FAILED"}
+
+// orderFinishedEvents are the events of the finished order statuses.
+var orderFinishedEvents = map[string]notify.EventType{
+	"CANCELLED": notify.EventOrderCancelled,
+	"EXPIRED":   notify.EventOrderExpired,
+	"FAILED":    notify.EventOrderFailed,
+}
+
+// orderEventStatuses are the audit statuses of the order events.
+var orderEventStatuses = map[notify.EventType]string{
+	notify.EventOrderFilled:    orderStatusFilled,
+	notify.EventOrderExpired:   orderStatusExpired,
+	notify.EventOrderCancelled: orderStatusCancelled,
+	notify.EventOrderFailed:    orderStatusFailed,
+}
+
+// trackedOrder is an order submitted by the liquidator that is polled
+// until it fills, is cancelled, fails, or expires. The price is the
+// Exchange price at decision time. The market TWAP is only sampled for
+// TWAP orders by the monitor loop.
+type trackedOrder struct {
+	orderId       string
+	clientOrderId string
+	productId     string
+	symbol        string
+	orderType     string
+	price         decimal.Decimal
+	limitPrice    decimal.Decimal
+	submitted     time.Time
+	expiry        time.Time
+	market        *marketTwap
+}
+
+// trackOrder notifies of the order submission result and starts tracking
+// the order if it was created. Duplicate orders are not resubmitted and
+// are skipped.
+func (l *Liquidator) trackOrder(
+	response *prime.CreateOrderResponse,
+	err error,
+	orderType,
+	productId string,
+	price,
+	value,
+	orderSize,
+	limitPrice decimal.Decimal,
+	duration time.Duration,
+	asset *prime.Balance,
+	o *Outcome,
+) (*Outcome, error) {
+
+	e := orderEvent(notify.EventOrderSubmitted, orderType, productId, asset)
+	e.Size = orderSize.String()
+	e.Value = value.String()
+	if !limitPrice.IsZero() {
+		e.LimitPrice = limitPrice.String()
+	}
+
+	var duplicate *caller.DuplicateOrderError
+	if errors.As(err, &duplicate) {
+		l.breaker.release()
+		o.ClientOrderId = duplicate.ClientOrderId
+		return o.skip(SkipDuplicateOrder), nil
+	}
+
+	if err != nil {
+		e.Type = notify.EventOrderFailed
+		l.notifier.Notify(e.WithError(err))
+		l.orderFailed(err)
+		return o, err
+	}
+
+	l.orderSucceeded()
+
+	e.OrderId = response.OrderId
+	e.ClientOrderId = response.Request.Order.ClientOrderId
+	l.notifier.Notify(e)
+
+	o.Action = ActionOrder
+	o.OrderId = e.OrderId
+	o.ClientOrderId = e.ClientOrderId
+
+	l.ordersLock.Lock()
+	defer l.ordersLock.Unlock()
+
+	tracked := &trackedOrder{
+		orderId:       response.OrderId,
+		clientOrderId: e.ClientOrderId,
+		productId:     productId,
+		symbol:        asset.Symbol,
+		orderType:     orderType,
+		price:         price,
+		limitPrice:    limitPrice,
+		submitted:     time.Now().UTC(),
+		expiry:        time.Now().Add(duration),
+	}
+
+	if orderType == prime.OrderTypeTwap {
+		tracked.market = newMarketTwap(price, tracked.submitted)
+	}
+
+	l.orders[response.OrderId] = tracked
+
+	return o, nil
+}
+
+// loadOpenOrders tracks the liquidator orders that are open in Prime and
+// not tracked yet, so orders submitted before a restart or by a previous
+// leader are recorded when they finish. The decision price is read from
+// the audit log, if configured. The market TWAP is not sampled for these
+// orders.
+func (l *Liquidator) loadOpenOrders() error {
+
+	open, err := l.call.PrimeDescribeOpenOrders("")
+	if err != nil {
+		return err
+	}
+
+	var orders []*prime.Order
+	for _, order := range open {
+		if caller.IsLiquidatorOrder(order.ClientOrderId) && !l.orderTracked(order.Id) {
+			orders = append(orders, order)
+		}
+	}
+
+	if len(orders) == 0 {
+		return nil
+	}
+
+	prices, err := l.decisionPrices(orders)
+	if err != nil {
+		zap.L().Warn("unable to read decision prices", zap.Error(err))
+	}
+
+	l.ordersLock.Lock()
+	defer l.ordersLock.Unlock()
+
+	for _, order := range orders {
+
+		tracked := openOrder(order, prices[order.Id])
+
+		zap.L().Info(
+			"tracking open order",
+			zap.String("orderId", tracked.orderId),
+			zap.String("clientOrderId", tracked.clientOrderId),
+			zap.String("productId", tracked.productId),
+		)
+
+		l.orders[order.Id] = tracked
+	}
+
+	return nil
+}
+
+// openOrder returns the tracked order of an order that is open in Prime.
+func openOrder(order *prime.Order, price decimal.Decimal) *trackedOrder {
+
+	tracked := &trackedOrder{
+		orderId:       order.Id,
+		clientOrderId: order.ClientOrderId,
+		productId:     order.ProductId,
+		symbol:        strings.Split(order.ProductId, "-")[0],
+		orderType:     order.Type,
+		price:         price,
+		submitted:     time.Now().UTC(),
+	}
+
+	tracked.limitPrice, _ = decimal.NewFromString(order.LimitPrice)
+
+	if created, err := time.Parse(time.RFC3339, order.Created); err == nil {
+		tracked.submitted = created.UTC()
+	}
+
+	tracked.expiry = tracked.submitted
+	if expiry, err := time.Parse(time.RFC3339, order.ExpiryTime); err == nil {
+		tracked.expiry = expiry
+	}
+
+	return tracked
+}
+
+// decisionPrices returns the Exchange price at decision time of the orders
+// from the decision records in the audit log, by order id. Empty if the
+// audit log is not configured.
+func (l *Liquidator) decisionPrices(orders []*prime.Order) (map[string]decimal.Decimal, error) {
+
+	prices := make(map[string]decimal.Decimal)
+
+	if len(l.config.AuditLogPath) == 0 {
+		return prices, nil
+	}
+
+	// Decisions are recorded before the orders are created
+	start := time.Now()
+	for _, order := range orders {
+		if created, err := time.Parse(time.RFC3339, order.Created); err == nil && created.Before(start) {
+			start = created
+		}
+	}
+
+	records, err := audit.ReadFile(l.config.AuditLogPath, start.Add(-time.Hour), time.Time{})
+	if err != nil {
+		return prices, err
+	}
+
+	for _, rec := range records {
+		if rec.Kind != audit.KindDecision || len(rec.OrderId) == 0 {
+			continue
+		}
+		if price, err := decimal.NewFromString(rec.Price); err == nil {
+			prices[rec.OrderId] = price
+		}
+	}
+
+	return prices, nil
+}
+
+// checkOrders looks up the tracked orders and notifies when they are
+// completely filled, are cancelled, fail, or expire. Orders that are
+// still open after their expiry and the grace period are considered
+// expired. The fills are written to the audit log. The open orders are
+// listed at most once per product on each pass.
+func (l *Liquidator) checkOrders() {
+
+	open := make(openOrders)
+
+	for id, tracked := range l.trackedOrders() {
+
+		order, err := l.call.PrimeDescribeOrder(id)
+		if err != nil {
+			zap.L().Error("unable to check order", zap.String("orderId", id), zap.Error(err))
+			continue
+		}
+
+		l.sampleMarket(tracked, time.Now())
+
+		eventType, status, err := l.orderFinished(tracked, order, open)
+		if err != nil {
+			zap.L().Error("unable to check order status", zap.String("orderId", id), zap.Error(err))
+			continue
+		}
+
+		if len(eventType) == 0 {
+			continue
+		}
+
+		e := orderEvent(eventType, tracked.orderType, tracked.productId, nil)
+		e.Symbol = tracked.symbol
+		e.OrderId = tracked.orderId
+		e.ClientOrderId = tracked.clientOrderId
+		e.Size = order.BaseQuantity
+		e.FilledQuantity = order.FilledQuantity
+		e.FilledValue = order.FilledValue
+		e.Status = status
+		l.notifier.Notify(e)
+
+		zap.L().Info(
+			"order complete",
+			zap.String("orderId", tracked.orderId),
+			zap.String("clientOrderId", tracked.clientOrderId),
+			zap.String("status", string(eventType)),
+			zap.String("filledQuantity", order.FilledQuantity),
+		)
+
+		l.appendAudit(orderRecord(tracked, order, eventType))
+
+		l.recordFills(tracked)
+
+		l.untrackOrder(id)
+	}
+}
+
+// openOrders are the ids of the open orders in Prime by product id.
+type openOrders map[string]map[string]bool
+
+// orderOpen returns true if the order is open in Prime. The open orders
+// of the product are listed the first time they are needed.
+func (l *Liquidator) orderOpen(tracked *trackedOrder, open openOrders) (bool, error) {
+
+	ids, ok := open[tracked.productId]
+	if !ok {
+
+		orders, err := l.call.PrimeDescribeOpenOrders(tracked.productId)
+		if err != nil {
+			return false, err
+		}
+
+		ids = make(map[string]bool, len(orders))
+		for _, o := range orders {
+			ids[o.Id] = true
+		}
+
+		open[tracked.productId] = ids
+	}
+
+	return ids[tracked.orderId], nil
+}
+
+// orderFinished returns the event of the order if it is completely
+// filled, was cancelled, failed, or expired in Prime, or is still open
+// after its expiry and the grace period. The event is empty if the order
+// is still working. The status is the Prime order status, if known.
+func (l *Liquidator) orderFinished(tracked *trackedOrder, order *prime.Order, open openOrders) (notify.EventType, string, error) {
+
+	filled, _ := decimal.NewFromString(order.FilledQuantity)
+	base, _ := decimal.NewFromString(order.BaseQuantity)
+
+	if base.IsPositive() && filled.GreaterThanOrEqual(base) {
+		return notify.EventOrderFilled, orderStatusPrimeFilled, nil
+	}
+
+	isOpen, err := l.orderOpen(tracked, open)
+	if err != nil {
+		return "", "", err
+	}
+
+	if !isOpen {
+
+		status, err := l.finishedOrderStatus(tracked)
+		if err != nil {
+			return "", "", err
+		}
+
+		if len(status) > 0 {
+			return orderFinishedEvents[status], status, nil
+		}
+	}
+
+	if time.Now().After(tracked.expiry.Add(orderCompletionGrace)) {
+		return notify.EventOrderExpired, "", nil
+	}
+
+	return "", "", nil
+}
+
+// finishedOrderStatus returns the Prime status of an order that is no
+// longer open and was cancelled, failed, or expired. The order is not
+// returned with its status by Prime, so the orders with each status are
+// listed until the order is found. Empty if the status is not found yet.
+func (l *Liquidator) finishedOrderStatus(tracked *trackedOrder) (string, error) {
+
+	start := tracked.submitted.Add(-time.Minute)
+
+	for _, status := range orderFinishedStatuses {
+
+		orders, err := l.call.PrimeDescribeOrders(status, tracked.productId, start)
+		if err != nil {
+			return "", err
+		}
+
+		for _, o := range orders {
+			if o.Id == tracked.orderId {
+				return status, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// trackedOrders returns a copy of the tracked orders so they can be
+// checked without holding the lock during Prime calls.
+func (l *Liquidator) trackedOrders() map[string]*trackedOrder {
+
+	l.ordersLock.Lock()
+	defer l.ordersLock.Unlock()
+
+	orders := make(map[string]*trackedOrder, len(l.orders))
+	for id, tracked := range l.orders {
+		orders[id] = tracked
+	}
+	return orders
+}
+
+// trackingOrders returns true if there are orders that have not filled
+// or expired.
+func (l *Liquidator) trackingOrders() bool {
+	l.ordersLock.Lock()
+	defer l.ordersLock.Unlock()
+	return len(l.orders) > 0
+}
+
+func (l *Liquidator) orderTracked(id string) bool {
+	l.ordersLock.Lock()
+	defer l.ordersLock.Unlock()
+	_, ok := l.orders[id]
+	return ok
+}
+
+func (l *Liquidator) untrackOrder(id string) {
+	l.ordersLock.Lock()
+	defer l.ordersLock.Unlock()
+	delete(l.orders, id)
+}
+
+// orderRecord returns the audit record of the completed order. The fees
+// are the Prime commission and the exchange fee.
+func orderRecord(tracked *trackedOrder, order *prime.Order, eventType notify.EventType) *audit.Record {
+
+	rec := audit.NewOrder(tracked.orderId, tracked.clientOrderId, tracked.symbol, tracked.productId, tracked.orderType)
+	rec.Status = orderEventStatuses[eventType]
+
+	rec.Side = order.Side
+	if tracked.price.IsPositive() {
+		rec.Price = tracked.price.String()
+	}
+	if !tracked.limitPrice.IsZero() {
+		rec.LimitPrice = tracked.limitPrice.String()
+	}
+	rec.OrderSize = order.BaseQuantity
+	rec.FilledQuantity = order.FilledQuantity
+	rec.FilledValue = order.FilledValue
+	rec.AveragePrice = order.AverageFilledPrice
+	rec.SubmittedAt = &tracked.submitted
+	if tracked.market != nil {
+		rec.MarketTwap = tracked.market.price().String()
+	}
+
+	commission, _ := decimal.NewFromString(order.Commission)
+	exchangeFee, _ := decimal.NewFromString(order.ExchangeFee)
+	rec.Fees = commission.Add(exchangeFee).String()
+
+	return rec
+}
+
+// recordFills writes the fills of the completed order to the audit log.
+// Fills are not looked up if the audit log is not configured.
+func (l *Liquidator) recordFills(tracked *trackedOrder) {
+
+	if len(l.config.AuditLogPath) == 0 {
+		return
+	}
+
+	fills, err := l.call.PrimeDescribeOrderFills(tracked.orderId)
+	if err != nil {
+		zap.L().Warn("unable to record order fills", zap.String("orderId", tracked.orderId), zap.Error(err))
+		return
+	}
+
+	for _, fill := range fills {
+		l.appendAudit(audit.NewFill(tracked.orderId, tracked.clientOrderId, tracked.symbol, tracked.orderType, fill))
+	}
+}
+
+func orderEvent(eventType notify.EventType, orderType, productId string, asset *prime.Balance) *notify.Event {
+	e := notify.NewEvent(eventType)
+	e.OrderType = orderType
+	e.ProductId = productId
+	if asset != nil {
+		e.Symbol = asset.Symbol
+	}
+	return e
+}
@@ -0,0 +1,323 @@
+/**
+ * Copyright 2023-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/audit"
+	"github.com/coinbase-samples/prime-liquidator-go/config"
+	"github.com/coinbase-samples/prime-liquidator-go/notify"
+	prime "github.com/coinbase-samples/prime-sdk-go"
+	"github.com/shopspring/decimal"
+)
+
+// orderCaller returns orders, open orders, orders by status, and fills as
+// configured and counts the order list calls. Other caller methods are
+// not implemented.
+type orderCaller struct {
+	catalogueCaller
+	orders    map[string]*prime.Order
+	open      []*prime.Order
+	statuses  map[string][]*prime.Order
+	fills     map[string][]*prime.OrderFill
+	openCalls int
+	listed    []string
+}
+
+func (c *orderCaller) PrimeDescribeOrder(orderId string) (*prime.Order, error) {
+	return c.orders[orderId], nil
+}
+
+func (c *orderCaller) PrimeDescribeOpenOrders(productId string) ([]*prime.Order, error) {
+	c.openCalls++
+	return c.open, nil
+}
+
+func (c *orderCaller) PrimeDescribeOrders(status, productId string, start time.Time) ([]*prime.Order, error) {
+	c.listed = append(c.listed, status)
+	return c.statuses[status], nil
+}
+
+func (c *orderCaller) PrimeDescribeOrderFills(orderId string) ([]*prime.OrderFill, error) {
+	return c.fills[orderId], nil
+}
+
+func TestCheckOrders(t *testing.T) {
+
+	cases := []struct {
+		description string
+		order       *prime.Order
+		open        bool
+		status      string
+		expiry      time.Duration
+		event       notify.EventType
+		recStatus   string
+		listed      int
+	}{
+		{
+			description: "TestCheckOrdersFilled",
+			order:       &prime.Order{Id: "order-1", BaseQuantity: "2", FilledQuantity: "2"},
+			expiry:      time.Hour,
+			event:       notify.EventOrderFilled,
+			recStatus:   orderStatusFilled,
+		},
+		{
+			description: "TestCheckOrdersCancelled",
+			order:       &prime.Order{Id: "order-1", BaseQuantity: "2", FilledQuantity: "0.5"},
+			status:      "CANCELLED",
+			expiry:      time.Hour,
+			event:       notify.EventOrderCancelled,
+			recStatus:   orderStatusCancelled,
+			listed:      1,
+		},
+		{
+			description: "TestCheckOrdersFailed",
+			order:       &prime.Order{Id: "order-1", BaseQuantity: "2", FilledQuantity: "0"},
+			status:      "FAILED",
+			expiry:      time.Hour,
+			event:       notify.EventOrderFailed,
+			recStatus:   orderStatusFailed,
+			listed:      3,
+		},
+		{
+			description: "TestCheckOrdersExpired",
+			order:       &prime.Order{Id: "order-1", BaseQuantity: "2", FilledQuantity: "1"},
+			status:      "EXPIRED",
+			expiry:      time.Hour,
+			event:       notify.EventOrderExpired,
+			recStatus:   orderStatusExpired,
+			listed:      2,
+		},
+		{
+			description: "TestCheckOrdersOpen",
+			order:       &prime.Order{Id: "order-1", BaseQuantity: "2", FilledQuantity: "1"},
+			open:        true,
+			expiry:      time.Hour,
+		},
+		{
+			description: "TestCheckOrdersOpenAfterExpiry",
+			order:       &prime.Order{Id: "order-1", BaseQuantity: "2", FilledQuantity: "1"},
+			open:        true,
+			expiry:      -time.Hour,
+			event:       notify.EventOrderExpired,
+			recStatus:   orderStatusExpired,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			call := &orderCaller{
+				orders:   map[string]*prime.Order{tt.order.Id: tt.order},
+				statuses: make(map[string][]*prime.Order),
+			}
+
+			if tt.open {
+				call.open = []*prime.Order{tt.order}
+			}
+
+			if len(tt.status) > 0 {
+				call.statuses[tt.status] = []*prime.Order{tt.order}
+			}
+
+			r := &recorder{}
+
+			l := &Liquidator{
+				config:   &config.AppConfig{},
+				call:     call,
+				audit:    r,
+				notifier: r,
+				orders: map[string]*trackedOrder{
+					tt.order.Id: {
+						orderId:   tt.order.Id,
+						productId: "ETH-USD",
+						symbol:    "ETH",
+						orderType: prime.OrderTypeMarket,
+						price:     decimal.NewFromInt(2000),
+						submitted: time.Now().UTC(),
+						expiry:    time.Now().Add(tt.expiry),
+					},
+				},
+			}
+
+			l.checkOrders()
+
+			// Finished statuses are only listed for orders that are not
+			// open, until the status is found
+			if len(call.listed) != tt.listed {
+				t.Errorf("test: %s - expected status lists: %d - received: %v", tt.description, tt.listed, call.listed)
+			}
+
+			if len(tt.event) == 0 {
+				if !l.trackingOrders() || len(r.events) != 0 {
+					t.Errorf("test: %s - expected the order to be tracked - received events: %d", tt.description, len(r.events))
+				}
+				return
+			}
+
+			if l.trackingOrders() {
+				t.Errorf("test: %s - expected the order to be finished", tt.description)
+			}
+
+			if len(r.events) != 1 || r.events[0].Type != tt.event {
+				t.Fatalf("test: %s - expected event: %s - received: %v", tt.description, tt.event, r.events)
+			}
+
+			if r.events[0].Status != tt.status && tt.event != notify.EventOrderFilled {
+				t.Errorf("test: %s - expected event status: %s - received: %s", tt.description, tt.status, r.events[0].Status)
+			}
+
+			if len(r.records) != 1 || r.records[0].Status != tt.recStatus {
+				t.Errorf("test: %s - expected record status: %s - received: %v", tt.description, tt.recStatus, r.records)
+			}
+		})
+	}
+}
+
+func TestCheckOrdersOpenPerProduct(t *testing.T) {
+
+	call := &orderCaller{
+		orders: map[string]*prime.Order{
+			"order-1": {Id: "order-1", BaseQuantity: "2", FilledQuantity: "1"},
+			"order-2": {Id: "order-2", BaseQuantity: "2", FilledQuantity: "1"},
+			"order-3": {Id: "order-3", BaseQuantity: "2", FilledQuantity: "2"},
+		},
+		open: []*prime.Order{{Id: "order-1"}, {Id: "order-2"}},
+	}
+
+	r := &recorder{}
+
+	l := &Liquidator{
+		config:   &config.AppConfig{},
+		call:     call,
+		audit:    r,
+		notifier: r,
+		orders:   make(map[string]*trackedOrder),
+	}
+
+	for id := range call.orders {
+		l.orders[id] = &trackedOrder{orderId: id, productId: "ETH-USD", symbol: "ETH", expiry: time.Now().Add(time.Hour)}
+	}
+
+	l.checkOrders()
+
+	if call.openCalls != 1 || len(call.listed) != 0 {
+		t.Errorf("expected the open orders to be listed once - received: %d open and %d status lists", call.openCalls, len(call.listed))
+	}
+
+	if !l.orderTracked("order-1") || !l.orderTracked("order-2") || l.orderTracked("order-3") {
+		t.Errorf("expected only the filled order to be finished")
+	}
+}
+
+func TestCheckOrdersAfterRestart(t *testing.T) {
+
+	created := time.Now().Add(-time.Hour).UTC()
+
+	// The decision to submit the order was recorded before the restart
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	store, err := audit.OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("cannot open audit log: %v", err)
+	}
+
+	decision := audit.NewDecision(&prime.Balance{Symbol: "ETH", Amount: "2"})
+	decision.Time = created.Add(-time.Second)
+	decision.Price = "2000"
+	decision.OrderId = "order-1"
+	if err := store.Append(decision); err != nil {
+		t.Fatalf("cannot write audit log: %v", err)
+	}
+	store.Close()
+
+	order := &prime.Order{
+		Id:             "order-1",
+		ClientOrderId:  "liquidator-1",
+		ProductId:      "ETH-USD",
+		Side:           prime.OrderSideSell,
+		Type:           prime.OrderTypeTwap,
+		BaseQuantity:   "2",
+		FilledQuantity: "1",
+		Created:        created.Format(time.RFC3339),
+		ExpiryTime:     created.Add(2 * time.Hour).Format(time.RFC3339),
+	}
+
+	call := &orderCaller{
+		orders: map[string]*prime.Order{order.Id: order},
+		// Orders not submitted by the liquidator are not tracked
+		open: []*prime.Order{order, {Id: "order-2", ClientOrderId: "manual-1", ProductId: "ETH-USD"}},
+		fills: map[string][]*prime.OrderFill{
+			"order-1": {
+				{Id: "fill-1", FilledQuantity: "1", FilledValue: "1990", Price: "1990"},
+				{Id: "fill-2", FilledQuantity: "1", FilledValue: "1995", Price: "1995"},
+			},
+		},
+	}
+
+	r := &recorder{}
+
+	l := &Liquidator{
+		config:   &config.AppConfig{AuditLogPath: path},
+		call:     call,
+		audit:    r,
+		notifier: r,
+		orders:   make(map[string]*trackedOrder),
+	}
+
+	if err := l.loadOpenOrders(); err != nil {
+		t.Fatalf("cannot load open orders: %v", err)
+	}
+
+	if !l.orderTracked("order-1") || l.orderTracked("order-2") {
+		t.Fatalf("expected only the liquidator order to be tracked")
+	}
+
+	l.checkOrders()
+
+	if len(r.records) != 0 {
+		t.Errorf("expected the open order to be tracked - received: %d records", len(r.records))
+	}
+
+	order.FilledQuantity = "2"
+	order.FilledValue = "3985"
+	call.open = nil
+
+	l.checkOrders()
+
+	if l.trackingOrders() {
+		t.Errorf("expected the order to be finished")
+	}
+
+	if len(r.records) != 3 {
+		t.Fatalf("expected an order and 2 fill records - received: %d", len(r.records))
+	}
+
+	rec := r.records[0]
+	if rec.Kind != audit.KindOrder || rec.Status != orderStatusFilled || rec.Price != "2000" || rec.SubmittedAt == nil || !rec.SubmittedAt.Equal(created.Truncate(time.Second)) {
+		t.Errorf("expected a filled order record with the decision price - received: %+v", rec)
+	}
+
+	for i, id := range []string{"fill-1", "fill-2"} {
+		if fill := r.records[i+1]; fill.Kind != audit.KindFill || fill.FillId != id || fill.OrderId != "order-1" {
+			t.Errorf("expected fill record: %s - received: %+v", id, fill)
+		}
+	}
+}
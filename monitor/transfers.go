@@ -0,0 +1,226 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"strings"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/audit"
+	"github.com/coinbase-samples/prime-liquidator-go/notify"
+	prime "github.com/coinbase-samples/prime-sdk-go"
+	"go.uber.org/zap"
+)
+
+const (
+	// transferTrackingLimit is how long a transfer is tracked before it is
+	// dropped without a final status. Transfers out of vault wallets can
+	// wait for approval for some time.
+	transferTrackingLimit = 72 * time.Hour
+
+	transferCompleted = "completed"
+	transferFailed    = "failed"
+)
+
+// trackedTransfer is a wallet transfer submitted by the liquidator that
+// is polled until its activity completes or fails. A new transfer is not
+// submitted from the wallet while the transfer is tracked.
+type trackedTransfer struct {
+	activityId  string
+	symbol      string
+	amount      string
+	walletId    string
+	destination string
+	submitted   time.Time
+}
+
+func (l *Liquidator) trackTransfer(activityId, symbol, amount, walletId, destination string) {
+
+	l.transfersLock.Lock()
+	defer l.transfersLock.Unlock()
+
+	l.transfers[activityId] = &trackedTransfer{
+		activityId:  activityId,
+		symbol:      symbol,
+		amount:      amount,
+		walletId:    walletId,
+		destination: destination,
+		submitted:   time.Now(),
+	}
+}
+
+// loadPendingTransfers tracks the transfers out of the vault wallets that
+// are still waiting for approval or processing in Prime. Transfers are
+// otherwise only tracked in memory, so without this a restart would
+// submit the same balance again under a new idempotency key.
+func (l *Liquidator) loadPendingTransfers(vaults []*prime.Wallet) error {
+
+	walletIds := make(map[string]bool, len(vaults))
+	for _, w := range vaults {
+		walletIds[w.Id] = true
+	}
+
+	activities, err := l.call.PrimeDescribePendingTransactionActivities(
+		l.config.VaultSweepSymbols(),
+		time.Now().Add(-transferTrackingLimit),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, activity := range activities {
+
+		if len(activity.ReferenceId) == 0 || l.transferTracked(activity.Id) {
+			continue
+		}
+
+		tx, err := l.call.PrimeDescribeTransaction(activity.ReferenceId)
+		if err != nil {
+			return err
+		}
+
+		if !walletIds[tx.WalletId] || strings.HasSuffix(tx.Type, "DEPOSIT") {
+			continue
+		}
+
+		var destination string
+		if tx.TransferTo != nil {
+			destination = tx.TransferTo.Value
+		}
+
+		l.trackTransfer(activity.Id, tx.Symbol, tx.Amount, tx.WalletId, destination)
+
+		zap.L().Info(
+			"pending transfer loaded",
+			zap.String("activityId", activity.Id),
+			zap.String("walletId", tx.WalletId),
+			zap.String("symbol", tx.Symbol),
+			zap.String("amount", tx.Amount),
+		)
+	}
+
+	return nil
+}
+
+// checkTransfers looks up the activity of each tracked transfer.
+// Completed and failed transfers are recorded, counted, and notified.
+func (l *Liquidator) checkTransfers() {
+
+	for id, tracked := range l.trackedTransfers() {
+
+		activity, err := l.call.PrimeDescribeActivity(id)
+		if err != nil {
+			zap.L().Error("unable to check transfer", zap.String("activityId", id), zap.Error(err))
+			continue
+		}
+
+		switch activity.Status {
+		case activityStatusCompleted:
+			l.transferFinished(tracked, transferCompleted, activity.Status)
+			l.untrackTransfer(id)
+
+		case activityStatusFailed, activityStatusCancelled, activityStatusExpired:
+			l.transferFinished(tracked, transferFailed, activity.Status)
+			l.untrackTransfer(id)
+
+		default:
+			if time.Since(tracked.submitted) > transferTrackingLimit {
+				zap.L().Warn("transfer no longer tracked", zap.String("activityId", id), zap.String("status", activity.Status))
+				l.untrackTransfer(id)
+			}
+		}
+	}
+}
+
+func (l *Liquidator) transferFinished(tracked *trackedTransfer, result, status string) {
+
+	transferCounts.Add(result, 1)
+
+	if l.deposits != nil {
+		l.deposits.finished(tracked.walletId, result == transferCompleted)
+	}
+
+	eventType := notify.EventTransferCompleted
+
+	rec := audit.NewTransfer(tracked.activityId, tracked.symbol, tracked.amount, tracked.walletId, tracked.destination)
+	rec.Status = result
+
+	if result == transferFailed {
+		eventType = notify.EventTransferFailed
+		rec.Error = status
+		zap.L().Error("transfer failed", zap.String("activityId", tracked.activityId), zap.String("status", status))
+	} else {
+		zap.L().Info(
+			"transfer completed",
+			zap.String("activityId", tracked.activityId),
+			zap.String("symbol", tracked.symbol),
+			zap.String("amount", tracked.amount),
+		)
+	}
+
+	e := notify.NewEvent(eventType)
+	e.Symbol = tracked.symbol
+	e.ActivityId = tracked.activityId
+	e.Size = tracked.amount
+	e.Destination = tracked.destination
+	e.Status = status
+	l.notifier.Notify(e)
+
+	l.appendAudit(rec)
+}
+
+// trackedTransfers returns a copy of the tracked transfers so they can
+// be checked without holding the lock during Prime calls.
+func (l *Liquidator) trackedTransfers() map[string]*trackedTransfer {
+
+	l.transfersLock.Lock()
+	defer l.transfersLock.Unlock()
+
+	transfers := make(map[string]*trackedTransfer, len(l.transfers))
+	for id, tracked := range l.transfers {
+		transfers[id] = tracked
+	}
+	return transfers
+}
+
+// transferPending returns true if a transfer from the wallet has not
+// completed or failed.
+func (l *Liquidator) transferPending(walletId string) bool {
+
+	l.transfersLock.Lock()
+	defer l.transfersLock.Unlock()
+
+	for _, tracked := range l.transfers {
+		if tracked.walletId == walletId {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Liquidator) transferTracked(id string) bool {
+	l.transfersLock.Lock()
+	defer l.transfersLock.Unlock()
+	_, ok := l.transfers[id]
+	return ok
+}
+
+func (l *Liquidator) untrackTransfer(id string) {
+	l.transfersLock.Lock()
+	defer l.transfersLock.Unlock()
+	delete(l.transfers, id)
+}
@@ -0,0 +1,80 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// triggerValues holds the minimum fiat value a balance must reach
+// before it is sold. Balances below the trigger accumulate until they
+// cross it or are liquidated by a dust sweep. Conversion balances are
+// compared in source units, which are the fiat value for the 1:1
+// stablecoin conversions that Prime supports.
+type triggerValues struct {
+	defaultValue decimal.Decimal
+	assets       map[string]decimal.Decimal
+}
+
+// newTriggerValues parses a comma separated list of asset trigger
+// values in the format "btc=500,eth=250".
+func newTriggerValues(defaultValue decimal.Decimal, spec string) (tv *triggerValues, err error) {
+
+	tv = &triggerValues{
+		defaultValue: defaultValue,
+		assets:       make(map[string]decimal.Decimal),
+	}
+
+	for _, v := range splitSpec(spec, ",") {
+
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 || len(strings.TrimSpace(parts[0])) == 0 {
+			err = fmt.Errorf("invalid asset trigger value: %s", v)
+			return
+		}
+
+		var value decimal.Decimal
+		if value, err = decimal.NewFromString(strings.TrimSpace(parts[1])); err != nil {
+			err = fmt.Errorf("invalid asset trigger value: %s - err: %w", v, err)
+			return
+		}
+
+		if value.IsNegative() {
+			err = fmt.Errorf("asset trigger value cannot be negative: %s", v)
+			return
+		}
+
+		tv.assets[strings.ToLower(strings.TrimSpace(parts[0]))] = value
+	}
+
+	return
+}
+
+func (tv *triggerValues) lookup(symbol string) decimal.Decimal {
+	if v, found := tv.assets[strings.ToLower(symbol)]; found {
+		return v
+	}
+	return tv.defaultValue
+}
+
+// below returns true if the value has not reached the asset's trigger.
+func (tv *triggerValues) below(symbol string, value decimal.Decimal) bool {
+	return value.LessThan(tv.lookup(symbol))
+}
@@ -0,0 +1,72 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestTriggerValuesBelow(t *testing.T) {
+
+	tv, err := newTriggerValues(decimal.NewFromInt(50), "btc=500, ETH=250")
+	if err != nil {
+		t.Fatalf("cannot create trigger values: %v", err)
+	}
+
+	cases := []struct {
+		description string
+		symbol      string
+		value       decimal.Decimal
+		expected    bool
+	}{
+		{
+			description: "TestTriggerValuesBelowAsset",
+			symbol:      "BTC",
+			value:       decimal.NewFromInt(499),
+			expected:    true,
+		},
+		{
+			description: "TestTriggerValuesEqualAsset",
+			symbol:      "eth",
+			value:       decimal.NewFromInt(250),
+			expected:    false,
+		},
+		{
+			description: "TestTriggerValuesBelowDefault",
+			symbol:      "sol",
+			value:       decimal.NewFromInt(49),
+			expected:    true,
+		},
+		{
+			description: "TestTriggerValuesAboveDefault",
+			symbol:      "sol",
+			value:       decimal.NewFromInt(51),
+			expected:    false,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			result := tv.below(tt.symbol, tt.value)
+			if result != tt.expected {
+				t.Errorf("test: %s - expected: %t - received: %t", tt.description, tt.expected, result)
+			}
+		})
+	}
+}
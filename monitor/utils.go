@@ -26,8 +26,25 @@ import (
 const (
 	euroSymbol = "eur"
 	usdSymbol  = "usd"
+
+	// minTwapDuration is the shortest TWAP that Prime accepts. If a
+	// trading window or blackout leaves less time, TWAP sized orders wait
+	// for the next window
+	minTwapDuration = 60 * time.Minute
 )
 
+// useTwap returns true if the value is sold with a TWAP over the
+// duration, which is the configured duration clamped to the trading
+// window. If the clamped duration is too short for a TWAP, the
+// configured duration decides, so large orders wait for the next window
+// rather than being sold at market.
+func useTwap(value decimal.Decimal, twapMinNotional int, configured, clamped time.Duration) bool {
+	if clamped < minTwapDuration {
+		return meetsTwapRequirements(value, twapMinNotional, configured)
+	}
+	return meetsTwapRequirements(value, twapMinNotional, clamped)
+}
+
 func meetsTwapRequirements(
 	value decimal.Decimal,
 	twapMinNotional int,
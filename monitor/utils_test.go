@@ -23,6 +23,32 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+func TestUseTwap(t *testing.T) {
+
+	cases := []struct {
+		description string
+		value       decimal.Decimal
+		clamped     time.Duration
+		expected    bool
+	}{
+		{description: "TestUseTwapFullDuration", value: decimal.NewFromInt(200), clamped: 2 * time.Hour, expected: true},
+		{description: "TestUseTwapBelowNotional", value: decimal.NewFromInt(150), clamped: 2 * time.Hour, expected: false},
+		{description: "TestUseTwapClampedRaisesRate", value: decimal.NewFromInt(150), clamped: time.Hour, expected: true},
+		{description: "TestUseTwapTooShortUsesConfigured", value: decimal.NewFromInt(200), clamped: 30 * time.Minute, expected: true},
+		{description: "TestUseTwapTooShortSmallOrder", value: decimal.NewFromInt(150), clamped: 30 * time.Minute, expected: false},
+		{description: "TestUseTwapWindowClosed", value: decimal.NewFromInt(150), clamped: 0, expected: false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			result := useTwap(tt.value, 100, 2*time.Hour, tt.clamped)
+			if result != tt.expected {
+				t.Errorf("test: %s - expected: %t - received: %t", tt.description, tt.expected, result)
+			}
+		})
+	}
+}
+
 func TestMeetsTwapRequirements(t *testing.T) {
 
 	cases := []struct {
@@ -0,0 +1,54 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"fmt"
+
+	"github.com/coinbase-samples/prime-liquidator-go/config"
+	"github.com/shopspring/decimal"
+)
+
+// ValidateConfig parses the trading schedule, conversion routes, and
+// asset trigger values, and returns a problem for each setting that is
+// invalid. These are the settings that are parsed by the liquidator
+// rather than the config package.
+func ValidateConfig(config *config.AppConfig) (problems []string) {
+
+	check := func(name string, err error) {
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+
+	_, err := newSchedule(config.TradingWindowsArray, "", "")
+	check("TRADING_WINDOWS", err)
+
+	_, err = newSchedule("", config.AssetTradingWindowsArray, "")
+	check("ASSET_TRADING_WINDOWS", err)
+
+	_, err = newSchedule("", "", config.BlackoutDatesArray)
+	check("BLACKOUT_DATES", err)
+
+	_, err = newConversionRoutes(config.ConvertSymbols(), config.FiatCurrencySymbol, config.ConversionRoutesArray)
+	check("CONVERSION_ROUTES", err)
+
+	_, err = newTriggerValues(decimal.Zero, config.AssetTriggerMinValuesArray)
+	check("ASSET_TRIGGER_MIN_VALUES", err)
+
+	return
+}
@@ -0,0 +1,72 @@
+/**
+ * Copyright 2023-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/coinbase-samples/prime-liquidator-go/config"
+)
+
+func TestValidateConfig(t *testing.T) {
+
+	cases := []struct {
+		description string
+		config      *config.AppConfig
+		expected    []string
+	}{
+		{
+			description: "TestValidateConfigValid",
+			config: &config.AppConfig{
+				FiatCurrencySymbol:         "usd",
+				ConvertSymbolsArray:        "usdc",
+				ConversionRoutesArray:      "pyusd->usd:min=100",
+				TradingWindowsArray:        "mon-fri 13:00-21:00",
+				AssetTriggerMinValuesArray: "btc=500",
+			},
+		},
+		{
+			description: "TestValidateConfigReportsEverySetting",
+			config: &config.AppConfig{
+				FiatCurrencySymbol:         "usd",
+				TradingWindowsArray:        "someday 13:00-21:00",
+				BlackoutDatesArray:         "2024-13-01",
+				ConversionRoutesArray:      "usd->usdc,usdc->usd",
+				AssetTriggerMinValuesArray: "btc",
+			},
+			expected: []string{"TRADING_WINDOWS", "BLACKOUT_DATES", "CONVERSION_ROUTES", "ASSET_TRIGGER_MIN_VALUES"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			problems := ValidateConfig(tt.config)
+
+			if len(problems) != len(tt.expected) {
+				t.Fatalf("test: %s - expected: %v - received: %v", tt.description, tt.expected, problems)
+			}
+
+			for i, name := range tt.expected {
+				if !strings.HasPrefix(problems[i], name+":") {
+					t.Errorf("test: %s - expected: %s - received: %s", tt.description, name, problems[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/notify"
+	prime "github.com/coinbase-samples/prime-sdk-go"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// vaultSweepDue returns true if vault symbols are configured and the
+// interval has elapsed since the last vault sweep.
+func (l *Liquidator) vaultSweepDue(now time.Time) bool {
+	if len(l.config.VaultSweepSymbols()) == 0 {
+		return false
+	}
+	return now.Sub(l.lastVaultSweep) >= l.config.VaultSweepInterval()
+}
+
+// depositTransactionSuffix matches the transaction types of deposits into
+// a wallet, e.g., DEPOSIT and INTERNAL_DEPOSIT.
+const depositTransactionSuffix = "DEPOSIT"
+
+// depositLookback is how long before the swept time deposit transactions
+// are listed, so deposits created before it and completed after it are
+// found.
+const depositLookback = 24 * time.Hour
+
+// depositSweep is the time through which the completed deposits of each
+// vault wallet have been swept, and the time the deposits of a submitted
+// transfer run through until it completes. The first time a wallet is
+// seen, after a start or a leadership change, deposits are swept from
+// then on, so balances already held in the vault are never moved. The
+// state is only accessed by the monitor loop.
+type depositSweep struct {
+	swept     map[string]time.Time
+	submitted map[string]time.Time
+}
+
+func newDepositSweep() *depositSweep {
+	return &depositSweep{
+		swept:     make(map[string]time.Time),
+		submitted: make(map[string]time.Time),
+	}
+}
+
+// since returns the time through which the deposits of the wallet have
+// been swept, starting from now the first time the wallet is seen.
+func (d *depositSweep) since(walletId string, now time.Time) time.Time {
+	if _, ok := d.swept[walletId]; !ok {
+		d.swept[walletId] = now
+	}
+	return d.swept[walletId]
+}
+
+// finished marks the deposits of the wallet's transfer as swept if the
+// transfer completed. If it failed, the deposits are swept again.
+func (d *depositSweep) finished(walletId string, completed bool) {
+	if through, ok := d.submitted[walletId]; ok && completed {
+		d.swept[walletId] = through
+	}
+	delete(d.submitted, walletId)
+}
+
+// sweepVaults transfers the deposits into each vault wallet for the
+// configured symbols to the trading wallet of the same symbol, so they
+// are liquidated on a later pass. The balance held in the vault before
+// the deposits is not moved. Wallets with a transfer that is waiting for
+// approval or processing are skipped, including transfers submitted
+// before a restart or by a previous leader.
+func (l *Liquidator) sweepVaults(now time.Time) error {
+
+	wallets, err := l.call.PrimeDescribeVaultWallets(l.config.VaultSweepSymbols())
+	if err != nil {
+		return fmt.Errorf("cannot describe vault wallets: %w", err)
+	}
+
+	if !l.transfersLoaded.Load() {
+		if err := l.loadPendingTransfers(wallets); err != nil {
+			return fmt.Errorf("cannot load pending transfers: %w", err)
+		}
+		l.deposits = newDepositSweep()
+		l.transfersLoaded.Store(true)
+	}
+
+	for _, w := range wallets {
+
+		if l.transferPending(w.Id) {
+			continue
+		}
+
+		if err := l.sweepVault(w, now); err != nil {
+			zap.L().Error("unable to sweep vault wallet", zap.String("walletId", w.Id), zap.String("symbol", w.Symbol), zap.Error(err))
+			if l.handleError(err) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (l *Liquidator) sweepVault(vault *prime.Wallet, now time.Time) error {
+
+	since := l.deposits.since(vault.Id, now)
+
+	deposits, through, err := l.depositsSince(vault, since)
+	if err != nil {
+		return err
+	}
+
+	if !deposits.IsPositive() {
+		return nil
+	}
+
+	balance, err := l.call.PrimeDescribeWalletBalance(vault.Id)
+	if err != nil {
+		return err
+	}
+
+	if balance == nil {
+		return nil
+	}
+
+	// The withdrawable amount excludes balances that are held or bonded
+	available := balance.WithdrawableAmount
+	if len(available) == 0 {
+		available = balance.Amount
+	}
+
+	availableNum, err := prime.Balance{Symbol: vault.Symbol, Amount: available}.AmountNum()
+	if err != nil {
+		return err
+	}
+
+	// Deposits that are not withdrawable yet are swept on a later pass
+	if availableNum.LessThan(deposits) {
+		return nil
+	}
+
+	amount := deposits
+
+	trading, err := l.catalogue.wallet(vault.Symbol)
+	if err != nil {
+		return err
+	}
+
+	if trading == nil {
+		return fmt.Errorf("trading wallet not found: %s", vault.Symbol)
+	}
+
+	e := notify.NewEvent(notify.EventTransferSubmitted)
+	e.Symbol = vault.Symbol
+	e.Size = amount.String()
+	e.Destination = trading.Id
+
+	response, err := l.call.PrimeCreateWalletTransfer(vault, trading, amount)
+	if err != nil {
+		e.Type = notify.EventTransferFailed
+		l.notifier.Notify(e.WithError(err))
+		return err
+	}
+
+	transferCounts.Add("submitted", 1)
+
+	l.trackTransfer(response.ActivityId, vault.Symbol, amount.String(), vault.Id, trading.Id)
+	l.deposits.submitted[vault.Id] = through
+
+	// Transfers that require approval are processed once approved in Prime
+	e.ActivityId = response.ActivityId
+	e.ApprovalUrl = response.ApprovalUrl
+	l.notifier.Notify(e)
+
+	return nil
+}
+
+// depositsSince returns the total of the deposits into the vault that
+// completed after since, and the completion time of the last of them.
+func (l *Liquidator) depositsSince(vault *prime.Wallet, since time.Time) (total decimal.Decimal, through time.Time, err error) {
+
+	transactions, err := l.call.PrimeDescribeWalletTransactions(vault.Id, since.Add(-depositLookback))
+	if err != nil {
+		return
+	}
+
+	for _, tx := range transactions {
+
+		if !strings.HasSuffix(tx.Type, depositTransactionSuffix) || tx.Status != transactionDone || !tx.Completed.After(since) {
+			continue
+		}
+
+		amount, aErr := decimal.NewFromString(tx.Amount)
+		if aErr != nil {
+			err = fmt.Errorf("invalid transaction amount: %s - transaction id: %s - err: %w", tx.Amount, tx.Id, aErr)
+			return
+		}
+
+		total = total.Add(amount.Abs())
+
+		if tx.Completed.After(through) {
+			through = tx.Completed
+		}
+	}
+
+	return
+}
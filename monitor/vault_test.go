@@ -0,0 +1,302 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/config"
+	"github.com/coinbase-samples/prime-liquidator-go/monitor/caller"
+	"github.com/coinbase-samples/prime-liquidator-go/notify"
+	prime "github.com/coinbase-samples/prime-sdk-go"
+	"github.com/shopspring/decimal"
+)
+
+// vaultCaller returns vault wallets and balances, records transfers, and
+// reports activities and transactions as configured. Other caller methods
+// are not implemented.
+type vaultCaller struct {
+	catalogueCaller
+	vaults       []*prime.Wallet
+	balances     map[string]*prime.Balance
+	activities   map[string]*prime.Activity
+	pending      []*prime.Activity
+	transactions map[string]*prime.Transaction
+	walletTxs    map[string][]*prime.Transaction
+	transfers    []string
+}
+
+func (c *vaultCaller) PrimeDescribeVaultWallets(symbols []string) ([]*prime.Wallet, error) {
+	return c.vaults, nil
+}
+
+func (c *vaultCaller) PrimeDescribeWalletBalance(walletId string) (*prime.Balance, error) {
+	return c.balances[walletId], nil
+}
+
+func (c *vaultCaller) PrimeCreateWalletTransfer(
+	sourceWallet,
+	destinationWallet *prime.Wallet,
+	amount decimal.Decimal,
+) (*prime.CreateWalletTransferResponse, error) {
+	c.transfers = append(c.transfers, sourceWallet.Id+"->"+destinationWallet.Id+":"+amount.String())
+	return &prime.CreateWalletTransferResponse{ActivityId: "activity-" + sourceWallet.Id, ApprovalUrl: "https://prime"}, nil
+}
+
+func (c *vaultCaller) PrimeDescribeActivity(activityId string) (*prime.Activity, error) {
+	return c.activities[activityId], nil
+}
+
+func (c *vaultCaller) PrimeDescribePendingTransactionActivities(symbols []string, start time.Time) ([]*prime.Activity, error) {
+	return c.pending, nil
+}
+
+func (c *vaultCaller) PrimeDescribeTransaction(transactionId string) (*prime.Transaction, error) {
+	return c.transactions[transactionId], nil
+}
+
+func (c *vaultCaller) PrimeDescribeWalletTransactions(walletId string, start time.Time) ([]*prime.Transaction, error) {
+	return c.walletTxs[walletId], nil
+}
+
+// deposit returns a completed deposit transaction into the wallet.
+func deposit(id, walletId, amount string, completed time.Time) *prime.Transaction {
+	return &prime.Transaction{
+		Id:        id,
+		WalletId:  walletId,
+		Type:      "DEPOSIT",
+		Status:    transactionDone,
+		Symbol:    "ETH",
+		Amount:    amount,
+		Completed: completed,
+	}
+}
+
+func TestSweepVaults(t *testing.T) {
+
+	start := time.Now()
+
+	call := &vaultCaller{
+		catalogueCaller: catalogueCaller{
+			wallets: caller.WalletLookup{"ETH": &prime.Wallet{Id: "eth-trading", Symbol: "ETH"}},
+		},
+		vaults: []*prime.Wallet{
+			{Id: "eth-vault-1", Symbol: "ETH"},
+			{Id: "eth-vault-2", Symbol: "ETH"},
+			{Id: "eth-vault-3", Symbol: "ETH"},
+		},
+		balances: map[string]*prime.Balance{
+			"eth-vault-1": {Symbol: "ETH", Amount: "5.5", WithdrawableAmount: "5"},
+			"eth-vault-2": {Symbol: "ETH", Amount: "0"},
+			"eth-vault-3": {Symbol: "ETH", Amount: "4"},
+		},
+		activities: map[string]*prime.Activity{
+			"activity-eth-vault-1": {Id: "activity-eth-vault-1", Status: "ACTIVITY_STATUS_PROCESSING"},
+			"activity-eth-vault-3": {Id: "activity-eth-vault-3", Status: activityStatusCompleted},
+		},
+		walletTxs: map[string][]*prime.Transaction{
+			"eth-vault-1": {
+				deposit("tx-1", "eth-vault-1", "2", start.Add(time.Minute)),
+				{Id: "tx-2", WalletId: "eth-vault-1", Type: "DEPOSIT", Status: "TRANSACTION_PROCESSING", Symbol: "ETH", Amount: "1"},
+			},
+			// The deposit completed before the sweep started is not swept
+			"eth-vault-3": {
+				deposit("tx-3", "eth-vault-3", "3", start.Add(-time.Minute)),
+				deposit("tx-4", "eth-vault-3", "1", start.Add(time.Minute)),
+			},
+		},
+	}
+
+	r := &recorder{}
+
+	l := &Liquidator{
+		config:    &config.AppConfig{VaultSweepSymbolsArray: "eth", VaultSweepIntervalInMinutes: "5"},
+		call:      call,
+		catalogue: newCatalogue(call, time.Hour, time.Hour),
+		audit:     r,
+		notifier:  r,
+		transfers: make(map[string]*trackedTransfer),
+	}
+
+	if !l.vaultSweepDue(start) {
+		t.Fatalf("expected the first vault sweep to be due")
+	}
+
+	if err := l.sweepVaults(start); err != nil {
+		t.Fatalf("cannot sweep vaults: %v", err)
+	}
+
+	expected := []string{"eth-vault-1->eth-trading:2", "eth-vault-3->eth-trading:1"}
+	if len(call.transfers) != len(expected) {
+		t.Fatalf("expected: %v - received: %v", expected, call.transfers)
+	}
+
+	for i, v := range expected {
+		if call.transfers[i] != v {
+			t.Errorf("expected: %s - received: %s", v, call.transfers[i])
+		}
+	}
+
+	for _, e := range r.events {
+		if e.Type != notify.EventTransferSubmitted || len(e.ApprovalUrl) == 0 {
+			t.Errorf("expected transfer submitted with approval url - received: %+v", e)
+		}
+	}
+
+	l.checkTransfers()
+
+	if !l.transferPending("eth-vault-1") || l.transferPending("eth-vault-3") {
+		t.Errorf("expected only the processing transfer to be pending")
+	}
+
+	// Pending transfers are not submitted again and swept deposits are
+	// not swept twice
+	if err := l.sweepVaults(start.Add(5 * time.Minute)); err != nil {
+		t.Fatalf("cannot sweep vaults: %v", err)
+	}
+
+	if len(call.transfers) != 2 {
+		t.Errorf("expected no new transfers - received: %v", call.transfers)
+	}
+
+	// Only the deposit after the completed transfer is swept
+	call.walletTxs["eth-vault-3"] = append(call.walletTxs["eth-vault-3"], deposit("tx-5", "eth-vault-3", "0.5", start.Add(6*time.Minute)))
+
+	if err := l.sweepVaults(start.Add(10 * time.Minute)); err != nil {
+		t.Fatalf("cannot sweep vaults: %v", err)
+	}
+
+	if len(call.transfers) != 3 || call.transfers[2] != "eth-vault-3->eth-trading:0.5" {
+		t.Errorf("expected only the new deposit to be swept - received: %v", call.transfers)
+	}
+
+	if len(r.records) != 1 || r.records[0].Status != transferCompleted {
+		t.Errorf("expected a completed transfer record - received: %d", len(r.records))
+	}
+}
+
+func TestSweepVaultsExistingBalance(t *testing.T) {
+
+	start := time.Now()
+
+	call := &vaultCaller{
+		catalogueCaller: catalogueCaller{
+			wallets: caller.WalletLookup{"ETH": &prime.Wallet{Id: "eth-trading", Symbol: "ETH"}},
+		},
+		vaults:   []*prime.Wallet{{Id: "eth-vault-1", Symbol: "ETH"}},
+		balances: map[string]*prime.Balance{"eth-vault-1": {Symbol: "ETH", Amount: "100", WithdrawableAmount: "100"}},
+		walletTxs: map[string][]*prime.Transaction{
+			"eth-vault-1": {deposit("tx-1", "eth-vault-1", "100", start.Add(-time.Hour))},
+		},
+	}
+
+	r := &recorder{}
+
+	l := &Liquidator{
+		config:    &config.AppConfig{VaultSweepSymbolsArray: "eth", VaultSweepIntervalInMinutes: "5"},
+		call:      call,
+		catalogue: newCatalogue(call, time.Hour, time.Hour),
+		audit:     r,
+		notifier:  r,
+		transfers: make(map[string]*trackedTransfer),
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := l.sweepVaults(start.Add(time.Duration(i*5) * time.Minute)); err != nil {
+			t.Fatalf("cannot sweep vaults: %v", err)
+		}
+	}
+
+	if len(call.transfers) != 0 || len(r.events) != 0 {
+		t.Errorf("expected no transfer of the existing balance - received: %v", call.transfers)
+	}
+}
+
+func TestSweepVaultsAfterRestart(t *testing.T) {
+
+	start := time.Now()
+
+	call := &vaultCaller{
+		catalogueCaller: catalogueCaller{
+			wallets: caller.WalletLookup{"ETH": &prime.Wallet{Id: "eth-trading", Symbol: "ETH"}},
+		},
+		vaults: []*prime.Wallet{
+			{Id: "eth-vault-1", Symbol: "ETH"},
+			{Id: "eth-vault-2", Symbol: "ETH"},
+		},
+		balances: map[string]*prime.Balance{
+			"eth-vault-1": {Symbol: "ETH", Amount: "2"},
+			"eth-vault-2": {Symbol: "ETH", Amount: "1"},
+		},
+		activities: map[string]*prime.Activity{
+			"activity-1":           {Id: "activity-1", Status: activityStatusCompleted},
+			"activity-eth-vault-2": {Id: "activity-eth-vault-2", Status: "ACTIVITY_STATUS_PROCESSING"},
+		},
+		// The transfer out of the first vault was submitted before the
+		// restart. The deposit into the second vault does not block it.
+		pending: []*prime.Activity{
+			{Id: "activity-1", ReferenceId: "tx-1", Status: "ACTIVITY_STATUS_PROCESSING"},
+			{Id: "activity-2", ReferenceId: "tx-2", Status: "ACTIVITY_STATUS_PROCESSING"},
+			{Id: "activity-3", Status: "ACTIVITY_STATUS_PROCESSING"},
+		},
+		transactions: map[string]*prime.Transaction{
+			"tx-1": {
+				Id:         "tx-1",
+				WalletId:   "eth-vault-1",
+				Type:       "TRANSFER",
+				Symbol:     "ETH",
+				Amount:     "2",
+				TransferTo: &prime.Transfer{Type: "WALLET", Value: "eth-trading"},
+			},
+			"tx-2": {Id: "tx-2", WalletId: "eth-vault-2", Type: "DEPOSIT", Symbol: "ETH", Amount: "1"},
+		},
+		walletTxs: map[string][]*prime.Transaction{
+			"eth-vault-2": {deposit("tx-2", "eth-vault-2", "1", start.Add(time.Minute))},
+		},
+	}
+
+	r := &recorder{}
+
+	l := &Liquidator{
+		config:    &config.AppConfig{VaultSweepSymbolsArray: "eth", VaultSweepIntervalInMinutes: "5"},
+		call:      call,
+		catalogue: newCatalogue(call, time.Hour, time.Hour),
+		audit:     r,
+		notifier:  r,
+		transfers: make(map[string]*trackedTransfer),
+	}
+
+	if err := l.sweepVaults(start); err != nil {
+		t.Fatalf("cannot sweep vaults: %v", err)
+	}
+
+	if len(call.transfers) != 1 || call.transfers[0] != "eth-vault-2->eth-trading:1" {
+		t.Errorf("expected only the wallet without a pending transfer to be swept - received: %v", call.transfers)
+	}
+
+	if !l.transferPending("eth-vault-1") {
+		t.Errorf("expected the transfer submitted before the restart to be pending")
+	}
+
+	// The loaded transfer is recorded when it completes
+	l.checkTransfers()
+
+	if len(r.records) != 1 || r.records[0].ActivityId != "activity-1" || r.records[0].Destination != "eth-trading" {
+		t.Errorf("expected a completed transfer record for the loaded transfer - received: %d", len(r.records))
+	}
+}
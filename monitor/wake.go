@@ -0,0 +1,78 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/feed"
+	"go.uber.org/zap"
+)
+
+// orderUpdated is called by the Prime feed when an order in the portfolio
+// changes. Fills and cancellations change the trading balances, so the
+// monitor loop is woken to check the orders and process the balances.
+func (l *Liquidator) orderUpdated(u *feed.OrderUpdate) {
+
+	zap.L().Debug(
+		"order update",
+		zap.String("orderId", u.OrderId),
+		zap.String("clientOrderId", u.ClientOrderId),
+		zap.String("status", u.Status),
+		zap.String("cumQty", u.CumQty),
+	)
+
+	l.wakeUp()
+}
+
+// wakeUp ends the current wait early. Wake ups received while the loop
+// is busy are coalesced into one.
+func (l *Liquidator) wakeUp() {
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+}
+
+// wait sleeps for the duration or until the loop is woken.
+func (l *Liquidator) wait(d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-l.wake:
+	}
+}
+
+// working returns true if orders or conversions are being tracked.
+func (l *Liquidator) working() bool {
+	return l.trackingOrders() || l.trackingConversions()
+}
+
+// pollInterval returns how long to wait between passes. While the
+// Prime feed is connected, order updates wake the loop, so working
+// orders do not speed up polling. Prime does not publish balance
+// updates on the feed, so deposits are picked up at the normal poll
+// interval.
+func (l *Liquidator) pollInterval() time.Duration {
+	working := l.working()
+	if l.feed != nil && l.feed.Connected() {
+		working = l.trackingConversions()
+	}
+	return l.poller.next(l.currentBalances(), working, l.config.PollMaxInterval())
+}
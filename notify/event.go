@@ -0,0 +1,87 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notify
+
+import (
+	"time"
+)
+
+type EventType string
+
+const (
+	EventOrderSubmitted      EventType = "order_submitted"
+	EventOrderFilled         EventType = "order_filled"
+	EventOrderExpired        EventType = "order_expired"
+	EventOrderCancelled      EventType = "order_cancelled"
+	EventOrderFailed         EventType = "order_failed"
+	EventConversionSubmitted EventType = "conversion_submitted"
+	EventConversionFailed    EventType = "conversion_failed"
+	EventConversionSettled   EventType = "conversion_settled"
+	EventConversionStuck     EventType = "conversion_stuck"
+	EventTransferSubmitted   EventType = "transfer_submitted"
+	EventTransferCompleted   EventType = "transfer_completed"
+	EventTransferFailed      EventType = "transfer_failed"
+	EventProceedsCapReached  EventType = "proceeds_sweep_cap_reached"
+	EventLoopError           EventType = "loop_error"
+	EventHalted              EventType = "halted"
+	EventBreakerOpened       EventType = "order_breaker_opened"
+	EventBreakerClosed       EventType = "order_breaker_closed"
+	EventPaused              EventType = "paused"
+	EventResumed             EventType = "resumed"
+	EventLeaderElected       EventType = "leader_elected"
+	EventLeaderLost          EventType = "leader_lost"
+)
+
+// Event is a structured liquidation event that is delivered to the
+// configured webhooks. Fields that do not apply to the event type
+// are omitted.
+type Event struct {
+	Type           EventType `json:"type"`
+	Time           time.Time `json:"time"`
+	Symbol         string    `json:"symbol,omitempty"`
+	ProductId      string    `json:"product_id,omitempty"`
+	OrderType      string    `json:"order_type,omitempty"`
+	OrderId        string    `json:"order_id,omitempty"`
+	ClientOrderId  string    `json:"client_order_id,omitempty"`
+	ActivityId     string    `json:"activity_id,omitempty"`
+	Size           string    `json:"size,omitempty"`
+	Value          string    `json:"value,omitempty"`
+	LimitPrice     string    `json:"limit_price,omitempty"`
+	FilledQuantity string    `json:"filled_quantity,omitempty"`
+	FilledValue    string    `json:"filled_value,omitempty"`
+	SettledAmount  string    `json:"settled_amount,omitempty"`
+	Fees           string    `json:"fees,omitempty"`
+	Status         string    `json:"status,omitempty"`
+	Destination    string    `json:"destination,omitempty"`
+	ApprovalUrl    string    `json:"approval_url,omitempty"`
+	Message        string    `json:"message,omitempty"`
+	Error          string    `json:"error,omitempty"`
+}
+
+// NewEvent returns a new event of the specified type stamped with the
+// current UTC time.
+func NewEvent(eventType EventType) *Event {
+	return &Event{Type: eventType, Time: time.Now().UTC()}
+}
+
+// WithError sets the error message on the event if err is not nil.
+func (e *Event) WithError(err error) *Event {
+	if err != nil {
+		e.Error = err.Error()
+	}
+	return e
+}
@@ -0,0 +1,81 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Formatter converts an event into a webhook request body.
+type Formatter interface {
+	Format(e *Event) ([]byte, error)
+}
+
+// JsonFormatter sends the event as is.
+type JsonFormatter struct{}
+
+func (f JsonFormatter) Format(e *Event) ([]byte, error) {
+	return json.Marshal(e)
+}
+
+// SlackFormatter sends the event as a Slack incoming webhook message.
+type SlackFormatter struct{}
+
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+func (f SlackFormatter) Format(e *Event) ([]byte, error) {
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "*prime-liquidator* %s", strings.ReplaceAll(string(e.Type), "_", " "))
+
+	fields := []struct {
+		name  string
+		value string
+	}{
+		{"symbol", e.Symbol},
+		{"product", e.ProductId},
+		{"type", e.OrderType},
+		{"size", e.Size},
+		{"value", e.Value},
+		{"limit price", e.LimitPrice},
+		{"filled quantity", e.FilledQuantity},
+		{"filled value", e.FilledValue},
+		{"settled amount", e.SettledAmount},
+		{"fees", e.Fees},
+		{"status", e.Status},
+		{"destination", e.Destination},
+		{"order id", e.OrderId},
+		{"client order id", e.ClientOrderId},
+		{"activity id", e.ActivityId},
+		{"approval url", e.ApprovalUrl},
+		{"message", e.Message},
+		{"error", e.Error},
+	}
+
+	for _, f := range fields {
+		if len(f.value) > 0 {
+			fmt.Fprintf(&b, "\n• %s: `%s`", f.name, f.value)
+		}
+	}
+
+	return json.Marshal(&slackMessage{Text: b.String()})
+}
@@ -0,0 +1,78 @@
+/**
+ * Copyright 2023-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notify
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSlackFormat(t *testing.T) {
+
+	cases := []struct {
+		description string
+		event       *Event
+		expected    []string
+	}{
+		{
+			description: "order fields",
+			event:       &Event{Type: EventOrderSubmitted, Symbol: "ETH", ProductId: "ETH-USD", Size: "1.5"},
+			expected:    []string{"*prime-liquidator* order submitted", "• symbol: `ETH`", "• product: `ETH-USD`", "• size: `1.5`"},
+		},
+		{
+			description: "transfer fields",
+			event: &Event{
+				Type:        EventTransferSubmitted,
+				Symbol:      "ETH",
+				ActivityId:  "activity-1",
+				Destination: "wallet-1",
+				ApprovalUrl: "https://prime.coinbase.com/approve",
+				Status:      "ACTIVITY_STATUS_PROCESSING",
+			},
+			expected: []string{
+				"• destination: `wallet-1`",
+				"• approval url: `https://prime.coinbase.com/approve`",
+				"• status: `ACTIVITY_STATUS_PROCESSING`",
+			},
+		},
+		{
+			description: "settlement fields",
+			event:       &Event{Type: EventConversionSettled, Symbol: "USDC", SettledAmount: "99.5", Fees: "0.5"},
+			expected:    []string{"• settled amount: `99.5`", "• fees: `0.5`"},
+		},
+	}
+
+	for _, tc := range cases {
+
+		body, err := SlackFormatter{}.Format(tc.event)
+		if err != nil {
+			t.Fatalf("test: %s - cannot format: %v", tc.description, err)
+		}
+
+		var m slackMessage
+		if err := json.Unmarshal(body, &m); err != nil {
+			t.Fatalf("test: %s - cannot unmarshal: %v", tc.description, err)
+		}
+
+		for _, line := range tc.expected {
+			if !strings.Contains(m.Text, line) {
+				t.Errorf("test: %s - expected: %s - received: %s", tc.description, line, m.Text)
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notify
+
+import (
+	"github.com/coinbase-samples/prime-liquidator-go/config"
+)
+
+// Notifier delivers liquidation events. Notify must not block the
+// caller on delivery.
+type Notifier interface {
+	Notify(e *Event)
+	Close() error
+}
+
+// NewNotifier returns a notifier that delivers events to all of the
+// configured webhooks. If no webhooks are configured, events are
+// discarded.
+func NewNotifier(config *config.AppConfig) Notifier {
+
+	var n multiNotifier
+
+	for _, u := range config.WebhookUrls() {
+		n = append(n, newWebhook(config, u, JsonFormatter{}))
+	}
+
+	for _, u := range config.SlackWebhookUrls() {
+		n = append(n, newWebhook(config, u, SlackFormatter{}))
+	}
+
+	return n
+}
+
+type multiNotifier []Notifier
+
+func (m multiNotifier) Notify(e *Event) {
+	for _, n := range m {
+		n.Notify(e)
+	}
+}
+
+func (m multiNotifier) Close() (err error) {
+	for _, n := range m {
+		if cErr := n.Close(); cErr != nil && err == nil {
+			err = cErr
+		}
+	}
+	return
+}
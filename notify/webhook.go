@@ -0,0 +1,168 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/config"
+	"go.uber.org/zap"
+)
+
+const (
+	SignatureHeader = "X-Liquidator-Signature"
+	TimestampHeader = "X-Liquidator-Timestamp"
+
+	webhookQueueSize     = 100
+	webhookRetryInterval = time.Second
+)
+
+// webhook posts events to a URL from a background goroutine so that
+// slow or unavailable endpoints do not hold up the monitor. Events are
+// dropped if the queue is full.
+type webhook struct {
+	url        string
+	secret     string
+	maxRetries int
+	timeout    time.Duration
+	formatter  Formatter
+	httpClient *http.Client
+	queue      chan *Event
+	wg         sync.WaitGroup
+}
+
+func newWebhook(config *config.AppConfig, url string, formatter Formatter) *webhook {
+
+	w := &webhook{
+		url:        url,
+		secret:     config.WebhookSigningSecret,
+		maxRetries: config.WebhookMaxRetries(),
+		timeout:    config.WebhookTimeout(),
+		formatter:  formatter,
+		httpClient: config.HttpClient,
+		queue:      make(chan *Event, webhookQueueSize),
+	}
+
+	w.wg.Add(1)
+	go w.run()
+
+	return w
+}
+
+func (w *webhook) Notify(e *Event) {
+	select {
+	case w.queue <- e:
+	default:
+		zap.L().Warn("webhook queue full - dropping event", zap.String("type", string(e.Type)))
+	}
+}
+
+// Close stops accepting events and waits for queued events to be sent.
+func (w *webhook) Close() error {
+	close(w.queue)
+	w.wg.Wait()
+	return nil
+}
+
+func (w *webhook) run() {
+
+	defer w.wg.Done()
+
+	for e := range w.queue {
+		if err := w.deliver(e); err != nil {
+			zap.L().Error(
+				"cannot deliver webhook event",
+				zap.String("type", string(e.Type)),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// deliver sends the event, retrying with a linear backoff on transport
+// errors and non-2xx responses.
+func (w *webhook) deliver(e *Event) (err error) {
+
+	body, err := w.formatter.Format(e)
+	if err != nil {
+		return fmt.Errorf("cannot format webhook event: %w", err)
+	}
+
+	for attempt := 0; attempt <= w.maxRetries; attempt++ {
+
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * webhookRetryInterval)
+		}
+
+		if err = w.post(body); err == nil {
+			return
+		}
+	}
+
+	return fmt.Errorf("webhook failed after %d attempts: %w", w.maxRetries+1, err)
+}
+
+func (w *webhook) post(body []byte) error {
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("cannot create webhook request: %w", err)
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+
+	if len(w.secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Add(TimestampHeader, timestamp)
+		req.Header.Add(SignatureHeader, Sign(w.secret, timestamp, body))
+	}
+
+	res, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot call webhook: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook did not return 2xx - val: %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the timestamp and body,
+// joined by a period, so receivers can verify the sender and reject
+// replayed requests.
+func Sign(secret, timestamp string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(timestamp))
+	h.Write([]byte("."))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
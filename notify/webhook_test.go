@@ -0,0 +1,75 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/coinbase-samples/prime-liquidator-go/config"
+)
+
+func TestWebhookDeliver(t *testing.T) {
+
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		body, _ := io.ReadAll(r.Body)
+
+		expected := Sign("secret", r.Header.Get(TimestampHeader), body)
+		if r.Header.Get(SignatureHeader) != expected {
+			t.Errorf("expected signature: %s - received: %s", expected, r.Header.Get(SignatureHeader))
+		}
+
+		var e Event
+		if err := json.Unmarshal(body, &e); err != nil || e.Type != EventOrderSubmitted {
+			t.Errorf("unexpected body: %s", string(body))
+		}
+
+		// Fail the first attempt to exercise the retry
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	w := newWebhook(
+		&config.AppConfig{
+			HttpClient:              server.Client(),
+			WebhookSigningSecret:    "secret",
+			WebhookMaxRetriesCount:  "1",
+			WebhookTimeoutInSeconds: "1",
+		},
+		server.URL,
+		JsonFormatter{},
+	)
+
+	w.Notify(NewEvent(EventOrderSubmitted))
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("cannot close webhook: %v", err)
+	}
+
+	if attempts.Load() != 2 {
+		t.Errorf("expected attempts: 2 - received: %d", attempts.Load())
+	}
+}
@@ -0,0 +1,195 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/audit"
+)
+
+const FormatCsv = "csv"
+
+// ExportColumns are the export columns in order. Columns are only ever
+// added at the end, so existing consumers are not affected.
+var ExportColumns = []string{
+	"record",
+	"timestamp",
+	"symbol",
+	"product",
+	"side",
+	"type",
+	"size",
+	"limit_price",
+	"avg_fill",
+	"fees",
+	"client_order_id",
+	"prime_order_id",
+	"status",
+	"filled_quantity",
+	"filled_value",
+	"fill_id",
+	"venue",
+	"activity_id",
+	"reference_price",
+}
+
+// ExportRow is an order, fill, or conversion performed by the liquidator.
+// The JSON names match the CSV columns. A fill's average fill is the fill
+// price. A conversion's product is the source and destination symbols,
+// its size is the amount submitted and its filled value is the settled
+// amount.
+type ExportRow struct {
+	Record         string `json:"record"`
+	Timestamp      string `json:"timestamp"`
+	Symbol         string `json:"symbol"`
+	Product        string `json:"product"`
+	Side           string `json:"side"`
+	Type           string `json:"type"`
+	Size           string `json:"size"`
+	LimitPrice     string `json:"limit_price"`
+	AvgFill        string `json:"avg_fill"`
+	Fees           string `json:"fees"`
+	ClientOrderId  string `json:"client_order_id"`
+	PrimeOrderId   string `json:"prime_order_id"`
+	Status         string `json:"status"`
+	FilledQuantity string `json:"filled_quantity"`
+	FilledValue    string `json:"filled_value"`
+	FillId         string `json:"fill_id"`
+	Venue          string `json:"venue"`
+	ActivityId     string `json:"activity_id"`
+	ReferencePrice string `json:"reference_price"`
+}
+
+// NewExport returns a row for each order, fill, and conversion record in
+// the audit log. Decision and transfer records are not exported.
+func NewExport(records []*audit.Record) []*ExportRow {
+
+	rows := make([]*ExportRow, 0, len(records))
+
+	for _, rec := range records {
+
+		row := &ExportRow{
+			Record:    string(rec.Kind),
+			Timestamp: rec.Time.UTC().Format(time.RFC3339Nano),
+			Symbol:    strings.ToUpper(rec.Symbol),
+			Product:   rec.ProductId,
+			Fees:      rec.Fees,
+			Status:    rec.Status,
+		}
+
+		switch rec.Kind {
+		case audit.KindOrder:
+			row.Side = rec.Side
+			row.Type = rec.OrderType
+			row.Size = rec.OrderSize
+			row.LimitPrice = rec.LimitPrice
+			row.AvgFill = rec.AveragePrice
+			row.ClientOrderId = rec.ClientOrderId
+			row.PrimeOrderId = rec.OrderId
+			row.FilledQuantity = rec.FilledQuantity
+			row.FilledValue = rec.FilledValue
+			row.ReferencePrice = rec.Price
+
+		case audit.KindFill:
+			row.Side = rec.Side
+			row.Type = rec.OrderType
+			row.Size = rec.FilledQuantity
+			row.AvgFill = rec.Price
+			row.ClientOrderId = rec.ClientOrderId
+			row.PrimeOrderId = rec.OrderId
+			row.FilledQuantity = rec.FilledQuantity
+			row.FilledValue = rec.FilledValue
+			row.FillId = rec.FillId
+			row.Venue = rec.Venue
+
+		case audit.KindConversion:
+			row.Type = "CONVERSION"
+			row.Size = rec.Amount
+			row.FilledValue = rec.SettledAmount
+			row.ActivityId = rec.ActivityId
+			if len(rec.Destination) > 0 {
+				row.Product = strings.ToUpper(rec.Symbol + "-" + rec.Destination)
+			}
+
+		default:
+			continue
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// WriteExport writes the rows in the format.
+func WriteExport(w io.Writer, rows []*ExportRow, format string) error {
+	switch format {
+	case FormatCsv:
+		return writeExportCsv(w, rows)
+	case FormatJson:
+		return writeJson(w, rows)
+	default:
+		return fmt.Errorf("unknown export format: %s", format)
+	}
+}
+
+func writeExportCsv(w io.Writer, rows []*ExportRow) error {
+
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(ExportColumns); err != nil {
+		return err
+	}
+
+	for _, r := range rows {
+		if err := cw.Write(r.values()); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func (r *ExportRow) values() []string {
+	return []string{
+		r.Record,
+		r.Timestamp,
+		r.Symbol,
+		r.Product,
+		r.Side,
+		r.Type,
+		r.Size,
+		r.LimitPrice,
+		r.AvgFill,
+		r.Fees,
+		r.ClientOrderId,
+		r.PrimeOrderId,
+		r.Status,
+		r.FilledQuantity,
+		r.FilledValue,
+		r.FillId,
+		r.Venue,
+		r.ActivityId,
+		r.ReferencePrice,
+	}
+}
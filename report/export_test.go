@@ -0,0 +1,114 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/audit"
+	prime "github.com/coinbase-samples/prime-sdk-go"
+)
+
+func TestExportColumns(t *testing.T) {
+
+	rt := reflect.TypeOf(ExportRow{})
+
+	if rt.NumField() != len(ExportColumns) {
+		t.Fatalf("expected a field for each column - received: %d - columns: %d", rt.NumField(), len(ExportColumns))
+	}
+
+	for i, column := range ExportColumns {
+		if tag := rt.Field(i).Tag.Get("json"); tag != column {
+			t.Errorf("expected: %s - received: %s", column, tag)
+		}
+	}
+
+	if n := len((&ExportRow{}).values()); n != len(ExportColumns) {
+		t.Errorf("expected a value for each column - received: %d", n)
+	}
+}
+
+func TestNewExport(t *testing.T) {
+
+	tm := time.Date(2024, 3, 4, 15, 0, 0, 0, time.UTC)
+
+	order := orderRecord(tm, "BTC", "order-1", "1", "60000", "30", "60500")
+	order.ClientOrderId = "client-1"
+	order.AveragePrice = "60000"
+
+	fill := audit.NewFill("order-1", "client-1", "BTC", "TWAP", &prime.OrderFill{
+		Id:             "fill-1",
+		Side:           "SELL",
+		ProductId:      "BTC-USD",
+		FilledQuantity: "1",
+		FilledValue:    "60000",
+		Price:          "60000",
+		Time:           tm,
+		Commission:     "30",
+	})
+
+	conversion := audit.NewConversion("activity-1", "usdc", "100")
+	conversion.Destination = "usd"
+	conversion.SettledAmount = "100"
+
+	decision := &audit.Record{Kind: audit.KindDecision, Time: tm, Symbol: "BTC"}
+
+	rows := NewExport([]*audit.Record{order, fill, conversion, decision})
+
+	cases := []struct {
+		description string
+		row         *ExportRow
+		record      string
+		product     string
+		avgFill     string
+		orderId     string
+	}{
+		{description: "TestNewExportOrder", row: rows[0], record: "order", product: "BTC-USD", avgFill: "60000", orderId: "order-1"},
+		{description: "TestNewExportFill", row: rows[1], record: "fill", product: "BTC-USD", avgFill: "60000", orderId: "order-1"},
+		{description: "TestNewExportConversion", row: rows[2], record: "conversion", product: "USDC-USD"},
+	}
+
+	if len(rows) != len(cases) {
+		t.Fatalf("expected decisions not to be exported - received: %d", len(rows))
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			if tt.row.Record != tt.record || tt.row.Product != tt.product || tt.row.AvgFill != tt.avgFill || tt.row.PrimeOrderId != tt.orderId {
+				t.Errorf("test: %s - received: %+v", tt.description, tt.row)
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := WriteExport(&buf, rows, FormatCsv); err != nil {
+		t.Fatalf("cannot write csv: %v", err)
+	}
+
+	lines, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("cannot read csv: %v", err)
+	}
+
+	if len(lines) != len(rows)+1 || !reflect.DeepEqual(lines[0], ExportColumns) {
+		t.Errorf("expected a header and a line for each row - received: %d", len(lines))
+	}
+}
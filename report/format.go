@@ -0,0 +1,176 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+const (
+	FormatText = "text"
+	FormatJson = "json"
+)
+
+// WriteProceeds writes the proceeds report in the format.
+func WriteProceeds(w io.Writer, p *Proceeds, format string) error {
+	switch format {
+	case FormatJson:
+		return writeJson(w, p)
+	case FormatText:
+		return writeProceedsText(w, p)
+	default:
+		return fmt.Errorf("unknown report format: %s", format)
+	}
+}
+
+// writeProceedsText writes a table with a row for each order, asset and
+// day, asset, and the total.
+func writeProceedsText(w io.Writer, p *Proceeds) error {
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "LEVEL\tDATE\tSYMBOL\tORDER\tORDERS\tFILLED\tAVG PRICE\tREF PRICE\tGROSS\tFEES\tNET\tSHORTFALL\tSHORTFALL BPS")
+
+	for _, o := range p.Orders {
+		fmt.Fprintf(
+			tw,
+			"order\t%s\t%s\t%s\t1\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			o.Date,
+			o.Symbol,
+			o.OrderId,
+			o.FilledQuantity,
+			o.AveragePrice,
+			o.ReferencePrice,
+			o.GrossProceeds,
+			o.Fees,
+			o.NetProceeds,
+			o.Shortfall,
+			o.ShortfallBps,
+		)
+	}
+
+	for _, s := range p.Days {
+		writeSummary(tw, "day", s)
+	}
+
+	for _, s := range p.Assets {
+		writeSummary(tw, "asset", s)
+	}
+
+	fmt.Fprintf(
+		tw,
+		"total\t\t\t\t%d\t\t\t\t%s\t%s\t%s\t%s\t%s\n",
+		p.Total.Orders,
+		p.Total.GrossProceeds,
+		p.Total.Fees,
+		p.Total.NetProceeds,
+		p.Total.Shortfall,
+		p.Total.ShortfallBps,
+	)
+
+	return tw.Flush()
+}
+
+// WriteTca writes the execution quality report in the format.
+func WriteTca(w io.Writer, tca *Tca, format string) error {
+	switch format {
+	case FormatJson:
+		return writeJson(w, tca)
+	case FormatText:
+		return writeTcaText(w, tca)
+	default:
+		return fmt.Errorf("unknown report format: %s", format)
+	}
+}
+
+// writeTcaText writes a table with a row for each order, strategy, and
+// asset and strategy.
+func writeTcaText(w io.Writer, tca *Tca) error {
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "LEVEL\tSYMBOL\tTYPE\tORDER\tORDERS\tFILL RATE\tSLIPPAGE BPS\tTWAP SLIPPAGE BPS\tAVG SECONDS\tMAX SECONDS")
+
+	for _, o := range tca.Orders {
+		fmt.Fprintf(
+			tw,
+			"order\t%s\t%s\t%s\t1\t%s\t%s\t%s\t%.0f\t%.0f\n",
+			o.Symbol,
+			o.OrderType,
+			o.OrderId,
+			o.FillRate,
+			o.SlippageBps,
+			o.TwapSlippageBps,
+			o.TimeToComplete,
+			o.TimeToComplete,
+		)
+	}
+
+	for _, s := range tca.AssetStrategies {
+		writeStrategy(tw, "asset", s)
+	}
+
+	for _, s := range tca.Strategies {
+		writeStrategy(tw, "strategy", s)
+	}
+
+	return tw.Flush()
+}
+
+func writeStrategy(w io.Writer, level string, s *StrategyExecution) {
+	fmt.Fprintf(
+		w,
+		"%s\t%s\t%s\t\t%d\t%s\t%s\t%s\t%.0f\t%.0f\n",
+		level,
+		s.Symbol,
+		s.OrderType,
+		s.Orders,
+		s.FillRate,
+		s.SlippageBps,
+		s.TwapSlippageBps,
+		s.AverageTimeToComplete,
+		s.MaxTimeToComplete,
+	)
+}
+
+func writeSummary(w io.Writer, level string, s *Summary) {
+	fmt.Fprintf(
+		w,
+		"%s\t%s\t%s\t\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+		level,
+		s.Date,
+		s.Symbol,
+		s.Orders,
+		s.FilledQuantity,
+		s.AveragePrice,
+		s.ReferencePrice,
+		s.GrossProceeds,
+		s.Fees,
+		s.NetProceeds,
+		s.Shortfall,
+		s.ShortfallBps,
+	)
+}
+
+func writeJson(w io.Writer, v interface{}) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(v)
+}
@@ -0,0 +1,218 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report
+
+import (
+	"sort"
+
+	"github.com/coinbase-samples/prime-liquidator-go/audit"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	dateLayout = "2006-01-02"
+
+	// shortfallDigits is the number of decimal places of quote currency
+	// values and prices in the report.
+	shortfallDigits = 8
+)
+
+var basisPoints = decimal.NewFromInt(10000)
+
+// Proceeds is the realised proceeds of the liquidator orders, per order,
+// per asset and day, and per asset. Proceeds are in the quote currency
+// of the products, which is the fiat currency.
+type Proceeds struct {
+	Orders []*OrderProceeds `json:"orders"`
+	Days   []*Summary       `json:"days"`
+	Assets []*Summary       `json:"assets"`
+	Total  *Summary         `json:"total"`
+}
+
+// OrderProceeds is the realised proceeds of a filled or expired order.
+// The reference price is the Exchange price when the order was submitted.
+// The implementation shortfall is the value at the reference price less
+// the gross proceeds, so a positive shortfall is a cost.
+type OrderProceeds struct {
+	Date           string          `json:"date"`
+	Symbol         string          `json:"symbol"`
+	ProductId      string          `json:"product_id"`
+	OrderId        string          `json:"order_id"`
+	OrderType      string          `json:"order_type"`
+	Status         string          `json:"status"`
+	FilledQuantity decimal.Decimal `json:"filled_quantity"`
+	GrossProceeds  decimal.Decimal `json:"gross_proceeds"`
+	Fees           decimal.Decimal `json:"fees"`
+	NetProceeds    decimal.Decimal `json:"net_proceeds"`
+	AveragePrice   decimal.Decimal `json:"average_price"`
+	ReferencePrice decimal.Decimal `json:"reference_price"`
+	Shortfall      decimal.Decimal `json:"shortfall"`
+	ShortfallBps   decimal.Decimal `json:"shortfall_bps"`
+}
+
+// Summary totals the proceeds of a group of orders. The average and
+// reference prices are weighted by the filled quantity. The date and
+// symbol are empty if the summary is not grouped by them.
+type Summary struct {
+	Date           string          `json:"date,omitempty"`
+	Symbol         string          `json:"symbol,omitempty"`
+	Orders         int             `json:"orders"`
+	FilledQuantity decimal.Decimal `json:"filled_quantity"`
+	GrossProceeds  decimal.Decimal `json:"gross_proceeds"`
+	Fees           decimal.Decimal `json:"fees"`
+	NetProceeds    decimal.Decimal `json:"net_proceeds"`
+	AveragePrice   decimal.Decimal `json:"average_price"`
+	ReferencePrice decimal.Decimal `json:"reference_price"`
+	Shortfall      decimal.Decimal `json:"shortfall"`
+	ShortfallBps   decimal.Decimal `json:"shortfall_bps"`
+
+	referenceValue decimal.Decimal
+}
+
+// NewProceeds calculates the proceeds from the order records in the audit
+// log. Other records and orders without fills are ignored. The total does
+// not include a quantity or prices, which are not comparable across
+// assets.
+func NewProceeds(records []*audit.Record) *Proceeds {
+
+	p := &Proceeds{Total: &Summary{}}
+
+	days := make(map[string]*Summary)
+	assets := make(map[string]*Summary)
+
+	for _, rec := range records {
+
+		o := newOrderProceeds(rec)
+		if o == nil {
+			continue
+		}
+
+		p.Orders = append(p.Orders, o)
+
+		day, found := days[o.Date+o.Symbol]
+		if !found {
+			day = &Summary{Date: o.Date, Symbol: o.Symbol}
+			days[o.Date+o.Symbol] = day
+			p.Days = append(p.Days, day)
+		}
+
+		asset, found := assets[o.Symbol]
+		if !found {
+			asset = &Summary{Symbol: o.Symbol}
+			assets[o.Symbol] = asset
+			p.Assets = append(p.Assets, asset)
+		}
+
+		for _, s := range []*Summary{day, asset, p.Total} {
+			s.add(o)
+		}
+	}
+
+	sort.SliceStable(p.Orders, func(i, j int) bool { return p.Orders[i].Date < p.Orders[j].Date })
+	sort.Slice(p.Days, func(i, j int) bool {
+		if p.Days[i].Date == p.Days[j].Date {
+			return p.Days[i].Symbol < p.Days[j].Symbol
+		}
+		return p.Days[i].Date < p.Days[j].Date
+	})
+	sort.Slice(p.Assets, func(i, j int) bool { return p.Assets[i].Symbol < p.Assets[j].Symbol })
+
+	for _, s := range p.Days {
+		s.finish()
+	}
+
+	for _, s := range p.Assets {
+		s.finish()
+	}
+
+	p.Total.finish()
+	p.Total.AveragePrice = decimal.Zero
+	p.Total.ReferencePrice = decimal.Zero
+	p.Total.FilledQuantity = decimal.Zero
+
+	return p
+}
+
+func newOrderProceeds(rec *audit.Record) *OrderProceeds {
+
+	if rec.Kind != audit.KindOrder {
+		return nil
+	}
+
+	filled := parseDecimal(rec.FilledQuantity)
+	if !filled.IsPositive() {
+		return nil
+	}
+
+	o := &OrderProceeds{
+		Date:           rec.Time.UTC().Format(dateLayout),
+		Symbol:         rec.Symbol,
+		ProductId:      rec.ProductId,
+		OrderId:        rec.OrderId,
+		OrderType:      rec.OrderType,
+		Status:         rec.Status,
+		FilledQuantity: filled,
+		GrossProceeds:  parseDecimal(rec.FilledValue),
+		Fees:           parseDecimal(rec.Fees),
+		ReferencePrice: parseDecimal(rec.Price),
+	}
+
+	o.NetProceeds = o.GrossProceeds.Sub(o.Fees)
+	o.AveragePrice = o.GrossProceeds.DivRound(filled, shortfallDigits)
+
+	// Orders recorded without a reference price have no shortfall
+	if o.ReferencePrice.IsPositive() {
+		referenceValue := o.ReferencePrice.Mul(filled)
+		o.Shortfall = referenceValue.Sub(o.GrossProceeds)
+		o.ShortfallBps = shortfallBps(o.Shortfall, referenceValue)
+	}
+
+	return o
+}
+
+func (s *Summary) add(o *OrderProceeds) {
+	s.Orders++
+	s.FilledQuantity = s.FilledQuantity.Add(o.FilledQuantity)
+	s.GrossProceeds = s.GrossProceeds.Add(o.GrossProceeds)
+	s.Fees = s.Fees.Add(o.Fees)
+	s.NetProceeds = s.NetProceeds.Add(o.NetProceeds)
+	s.Shortfall = s.Shortfall.Add(o.Shortfall)
+	s.referenceValue = s.referenceValue.Add(o.ReferencePrice.Mul(o.FilledQuantity))
+}
+
+func (s *Summary) finish() {
+	if s.FilledQuantity.IsPositive() {
+		s.AveragePrice = s.GrossProceeds.DivRound(s.FilledQuantity, shortfallDigits)
+		s.ReferencePrice = s.referenceValue.DivRound(s.FilledQuantity, shortfallDigits)
+	}
+	s.ShortfallBps = shortfallBps(s.Shortfall, s.referenceValue)
+}
+
+func shortfallBps(shortfall, referenceValue decimal.Decimal) decimal.Decimal {
+	if !referenceValue.IsPositive() {
+		return decimal.Zero
+	}
+	return shortfall.Mul(basisPoints).DivRound(referenceValue, 2)
+}
+
+func parseDecimal(v string) decimal.Decimal {
+	d, err := decimal.NewFromString(v)
+	if err != nil {
+		return decimal.Zero
+	}
+	return d
+}
@@ -0,0 +1,147 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/audit"
+	"github.com/shopspring/decimal"
+)
+
+func TestNewProceeds(t *testing.T) {
+
+	day := time.Date(2024, 3, 4, 15, 0, 0, 0, time.UTC)
+
+	records := []*audit.Record{
+		orderRecord(day, "BTC", "order-1", "1", "60000", "30", "60500"),
+		orderRecord(day, "BTC", "order-2", "0.5", "30250", "15", "60000"),
+		orderRecord(day.AddDate(0, 0, 1), "ETH", "order-3", "10", "30000", "15", "3000"),
+		orderRecord(day, "SOL", "order-4", "0", "0", "0", "150"),
+		{Kind: audit.KindDecision, Time: day, Symbol: "BTC", Action: "order"},
+	}
+
+	p := NewProceeds(records)
+
+	if len(p.Orders) != 3 {
+		t.Fatalf("expected orders with fills - received: %d", len(p.Orders))
+	}
+
+	cases := []struct {
+		description string
+		summary     *Summary
+		orders      int
+		net         string
+		average     string
+		reference   string
+		shortfall   string
+		bps         string
+	}{
+		{
+			description: "TestNewProceedsAsset",
+			summary:     p.Assets[0],
+			orders:      2,
+			net:         "90205",
+			average:     "60166.66666667",
+			reference:   "60333.33333333",
+			shortfall:   "250",
+			bps:         "27.62",
+		},
+		{
+			description: "TestNewProceedsDay",
+			summary:     p.Days[1],
+			orders:      1,
+			net:         "29985",
+			average:     "3000",
+			reference:   "3000",
+			shortfall:   "0",
+			bps:         "0",
+		},
+		{
+			description: "TestNewProceedsTotal",
+			summary:     p.Total,
+			orders:      3,
+			net:         "120190",
+			average:     "0",
+			reference:   "0",
+			shortfall:   "250",
+			bps:         "20.75",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			s := tt.summary
+
+			if s.Orders != tt.orders {
+				t.Errorf("test: %s - expected orders: %d - received: %d", tt.description, tt.orders, s.Orders)
+			}
+
+			for _, v := range []struct {
+				name     string
+				expected string
+				received decimal.Decimal
+			}{
+				{name: "net", expected: tt.net, received: s.NetProceeds},
+				{name: "average", expected: tt.average, received: s.AveragePrice},
+				{name: "reference", expected: tt.reference, received: s.ReferencePrice},
+				{name: "shortfall", expected: tt.shortfall, received: s.Shortfall},
+				{name: "bps", expected: tt.bps, received: s.ShortfallBps},
+			} {
+				if !v.received.Equal(decimal.RequireFromString(v.expected)) {
+					t.Errorf("test: %s - expected %s: %s - received: %s", tt.description, v.name, v.expected, v.received)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRange(t *testing.T) {
+
+	cases := []struct {
+		description string
+		from        string
+		to          string
+		expectError bool
+	}{
+		{description: "TestParseRangeUnbounded"},
+		{description: "TestParseRangeSameDay", from: "2024-03-01", to: "2024-03-01"},
+		{description: "TestParseRangeReversed", from: "2024-03-02", to: "2024-03-01", expectError: true},
+		{description: "TestParseRangeInvalid", from: "03/01/2024", expectError: true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			if _, _, err := ParseRange(tt.from, tt.to); (err != nil) != tt.expectError {
+				t.Errorf("test: %s - expected error: %t - received: %v", tt.description, tt.expectError, err)
+			}
+		})
+	}
+}
+
+func orderRecord(tm time.Time, symbol, orderId, filled, value, fees, price string) *audit.Record {
+	rec := audit.NewOrder(orderId, "", symbol, symbol+"-USD", "TWAP")
+	rec.Time = tm
+	rec.Status = "filled"
+	rec.FilledQuantity = filled
+	rec.FilledValue = value
+	rec.Fees = fees
+	rec.Price = price
+	return rec
+}
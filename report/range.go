@@ -0,0 +1,49 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report
+
+import (
+	"fmt"
+	"time"
+)
+
+// ParseRange returns the start and end times of the UTC dates in the
+// format YYYY-MM-DD. The end date is included, so the end time is the
+// start of the following day. An empty date is not bounded.
+func ParseRange(from, to string) (start, end time.Time, err error) {
+
+	if len(from) > 0 {
+		if start, err = time.Parse(dateLayout, from); err != nil {
+			err = fmt.Errorf("invalid from date: %s - err: %w", from, err)
+			return
+		}
+	}
+
+	if len(to) > 0 {
+		if end, err = time.Parse(dateLayout, to); err != nil {
+			err = fmt.Errorf("invalid to date: %s - err: %w", to, err)
+			return
+		}
+		end = end.AddDate(0, 0, 1)
+	}
+
+	if !start.IsZero() && !end.IsZero() && !start.Before(end) {
+		err = fmt.Errorf("from date is after to date: %s - %s", from, to)
+	}
+
+	return
+}
@@ -0,0 +1,255 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/audit"
+	"github.com/shopspring/decimal"
+)
+
+const sideBuy = "BUY"
+
+// Tca is the execution quality of the liquidator orders, per order, per
+// order strategy, and per asset and strategy.
+type Tca struct {
+	Orders          []*OrderExecution    `json:"orders"`
+	Strategies      []*StrategyExecution `json:"strategies"`
+	AssetStrategies []*StrategyExecution `json:"asset_strategies"`
+}
+
+// OrderExecution is the execution quality of an order. Slippage is
+// measured against the arrival price, which is the Exchange price at
+// decision time, and TWAP slippage against the time weighted Exchange
+// price while a TWAP order was working. Slippage is in basis points and
+// a positive slippage is a cost. The time to complete is from submission
+// to the last fill, or to when the order was found complete if its fills
+// were not recorded.
+type OrderExecution struct {
+	Date             string          `json:"date"`
+	Symbol           string          `json:"symbol"`
+	ProductId        string          `json:"product_id"`
+	OrderId          string          `json:"order_id"`
+	OrderType        string          `json:"order_type"`
+	Status           string          `json:"status"`
+	OrderSize        decimal.Decimal `json:"order_size"`
+	FilledQuantity   decimal.Decimal `json:"filled_quantity"`
+	FillRate         decimal.Decimal `json:"fill_rate"`
+	ArrivalPrice     decimal.Decimal `json:"arrival_price"`
+	AveragePrice     decimal.Decimal `json:"average_price"`
+	MarketTwap       decimal.Decimal `json:"market_twap"`
+	SlippageBps      decimal.Decimal `json:"slippage_bps"`
+	TwapSlippageBps  decimal.Decimal `json:"twap_slippage_bps"`
+	TimeToComplete   float64         `json:"time_to_complete_seconds"`
+	hasTwapReference bool
+}
+
+// StrategyExecution summarises the orders of a strategy. The fill rate is
+// the filled quantity over the order size, and the slippage is weighted by
+// the filled quantity. The symbol is empty if the summary is across all
+// assets.
+type StrategyExecution struct {
+	Symbol                string          `json:"symbol,omitempty"`
+	OrderType             string          `json:"order_type"`
+	Orders                int             `json:"orders"`
+	FillRate              decimal.Decimal `json:"fill_rate"`
+	SlippageBps           decimal.Decimal `json:"slippage_bps"`
+	TwapSlippageBps       decimal.Decimal `json:"twap_slippage_bps"`
+	AverageTimeToComplete float64         `json:"average_time_to_complete_seconds"`
+	MaxTimeToComplete     float64         `json:"max_time_to_complete_seconds"`
+
+	orderSize      decimal.Decimal
+	filled         decimal.Decimal
+	slippage       decimal.Decimal
+	twapFilled     decimal.Decimal
+	twapSlippage   decimal.Decimal
+	completeTotal  float64
+	completeOrders int
+}
+
+// NewTca calculates the execution quality from the order and fill records
+// in the audit log. Orders without an arrival price are not included.
+func NewTca(records []*audit.Record) *Tca {
+
+	lastFills := make(map[string]time.Time)
+	for _, rec := range records {
+		if rec.Kind == audit.KindFill && rec.Time.After(lastFills[rec.OrderId]) {
+			lastFills[rec.OrderId] = rec.Time
+		}
+	}
+
+	tca := &Tca{}
+
+	strategies := make(map[string]*StrategyExecution)
+	assetStrategies := make(map[string]*StrategyExecution)
+
+	for _, rec := range records {
+
+		o := newOrderExecution(rec, lastFills[rec.OrderId])
+		if o == nil {
+			continue
+		}
+
+		tca.Orders = append(tca.Orders, o)
+
+		strategy, found := strategies[o.OrderType]
+		if !found {
+			strategy = &StrategyExecution{OrderType: o.OrderType}
+			strategies[o.OrderType] = strategy
+			tca.Strategies = append(tca.Strategies, strategy)
+		}
+
+		assetStrategy, found := assetStrategies[o.Symbol+"-"+o.OrderType]
+		if !found {
+			assetStrategy = &StrategyExecution{Symbol: o.Symbol, OrderType: o.OrderType}
+			assetStrategies[o.Symbol+"-"+o.OrderType] = assetStrategy
+			tca.AssetStrategies = append(tca.AssetStrategies, assetStrategy)
+		}
+
+		strategy.add(o)
+		assetStrategy.add(o)
+	}
+
+	sort.SliceStable(tca.Orders, func(i, j int) bool { return tca.Orders[i].Date < tca.Orders[j].Date })
+	sort.Slice(tca.Strategies, func(i, j int) bool { return tca.Strategies[i].OrderType < tca.Strategies[j].OrderType })
+	sort.Slice(tca.AssetStrategies, func(i, j int) bool {
+		a, b := tca.AssetStrategies[i], tca.AssetStrategies[j]
+		if a.Symbol == b.Symbol {
+			return a.OrderType < b.OrderType
+		}
+		return a.Symbol < b.Symbol
+	})
+
+	for _, s := range tca.Strategies {
+		s.finish()
+	}
+
+	for _, s := range tca.AssetStrategies {
+		s.finish()
+	}
+
+	return tca
+}
+
+func newOrderExecution(rec *audit.Record, lastFill time.Time) *OrderExecution {
+
+	if rec.Kind != audit.KindOrder {
+		return nil
+	}
+
+	o := &OrderExecution{
+		Date:           rec.Time.UTC().Format(dateLayout),
+		Symbol:         rec.Symbol,
+		ProductId:      rec.ProductId,
+		OrderId:        rec.OrderId,
+		OrderType:      rec.OrderType,
+		Status:         rec.Status,
+		OrderSize:      parseDecimal(rec.OrderSize),
+		FilledQuantity: parseDecimal(rec.FilledQuantity),
+		ArrivalPrice:   parseDecimal(rec.Price),
+		AveragePrice:   parseDecimal(rec.AveragePrice),
+		MarketTwap:     parseDecimal(rec.MarketTwap),
+	}
+
+	if !o.ArrivalPrice.IsPositive() {
+		return nil
+	}
+
+	if o.OrderSize.IsPositive() {
+		o.FillRate = o.FilledQuantity.DivRound(o.OrderSize, 4)
+	}
+
+	if o.FilledQuantity.IsPositive() {
+
+		// Prefer the average from the filled value, which is more precise
+		if value := parseDecimal(rec.FilledValue); value.IsPositive() {
+			o.AveragePrice = value.DivRound(o.FilledQuantity, shortfallDigits)
+		}
+
+		buy := strings.EqualFold(rec.Side, sideBuy)
+
+		o.SlippageBps = slippageBps(o.ArrivalPrice, o.AveragePrice, buy)
+
+		if o.MarketTwap.IsPositive() {
+			o.TwapSlippageBps = slippageBps(o.MarketTwap, o.AveragePrice, buy)
+			o.hasTwapReference = true
+		}
+	}
+
+	if rec.SubmittedAt != nil {
+		completed := rec.Time
+		if !lastFill.IsZero() {
+			completed = lastFill
+		}
+		o.TimeToComplete = completed.Sub(*rec.SubmittedAt).Seconds()
+	}
+
+	return o
+}
+
+func (s *StrategyExecution) add(o *OrderExecution) {
+
+	s.Orders++
+	s.orderSize = s.orderSize.Add(o.OrderSize)
+	s.filled = s.filled.Add(o.FilledQuantity)
+	s.slippage = s.slippage.Add(o.SlippageBps.Mul(o.FilledQuantity))
+
+	if o.hasTwapReference {
+		s.twapFilled = s.twapFilled.Add(o.FilledQuantity)
+		s.twapSlippage = s.twapSlippage.Add(o.TwapSlippageBps.Mul(o.FilledQuantity))
+	}
+
+	if o.TimeToComplete > 0 {
+		s.completeTotal += o.TimeToComplete
+		s.completeOrders++
+		if o.TimeToComplete > s.MaxTimeToComplete {
+			s.MaxTimeToComplete = o.TimeToComplete
+		}
+	}
+}
+
+func (s *StrategyExecution) finish() {
+
+	if s.orderSize.IsPositive() {
+		s.FillRate = s.filled.DivRound(s.orderSize, 4)
+	}
+
+	if s.filled.IsPositive() {
+		s.SlippageBps = s.slippage.DivRound(s.filled, 2)
+	}
+
+	if s.twapFilled.IsPositive() {
+		s.TwapSlippageBps = s.twapSlippage.DivRound(s.twapFilled, 2)
+	}
+
+	if s.completeOrders > 0 {
+		s.AverageTimeToComplete = s.completeTotal / float64(s.completeOrders)
+	}
+}
+
+// slippageBps returns the cost of the execution price against the
+// reference price in basis points.
+func slippageBps(reference, price decimal.Decimal, buy bool) decimal.Decimal {
+	cost := reference.Sub(price)
+	if buy {
+		cost = cost.Neg()
+	}
+	return cost.Mul(basisPoints).DivRound(reference, 2)
+}
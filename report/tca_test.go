@@ -0,0 +1,120 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-liquidator-go/audit"
+	prime "github.com/coinbase-samples/prime-sdk-go"
+	"github.com/shopspring/decimal"
+)
+
+func TestNewTca(t *testing.T) {
+
+	submitted := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	twap := tcaRecord(submitted, "BTC", "order-1", prime.OrderTypeTwap, "1", "1", "99", "100")
+	twap.MarketTwap = "98"
+	twap.Time = submitted.Add(65 * time.Minute)
+
+	partial := tcaRecord(submitted, "BTC", "order-2", prime.OrderTypeTwap, "2", "1", "101", "100")
+	partial.MarketTwap = "100"
+	partial.Time = submitted.Add(70 * time.Minute)
+
+	market := tcaRecord(submitted, "ETH", "order-3", prime.OrderTypeMarket, "10", "10", "19990", "2000")
+	market.Time = submitted.Add(time.Minute)
+
+	fill := audit.NewFill("order-1", "", "BTC", prime.OrderTypeTwap, &prime.OrderFill{Time: submitted.Add(60 * time.Minute)})
+
+	tca := NewTca([]*audit.Record{twap, partial, market, fill})
+
+	if len(tca.Orders) != 3 {
+		t.Fatalf("expected three orders - received: %d", len(tca.Orders))
+	}
+
+	cases := []struct {
+		description string
+		strategy    *StrategyExecution
+		orders      int
+		fillRate    string
+		slippage    string
+		twap        string
+		average     float64
+	}{
+		{
+			description: "TestNewTcaTwap",
+			strategy:    tca.Strategies[1],
+			orders:      2,
+			fillRate:    "0.6667",
+			slippage:    "0",
+			twap:        "-101.02",
+			average:     3900,
+		},
+		{
+			description: "TestNewTcaMarket",
+			strategy:    tca.Strategies[0],
+			orders:      1,
+			fillRate:    "1",
+			slippage:    "5",
+			twap:        "0",
+			average:     60,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			s := tt.strategy
+
+			if s.Orders != tt.orders {
+				t.Errorf("test: %s - expected orders: %d - received: %d", tt.description, tt.orders, s.Orders)
+			}
+
+			for _, v := range []struct {
+				name     string
+				expected string
+				received decimal.Decimal
+			}{
+				{name: "fill rate", expected: tt.fillRate, received: s.FillRate},
+				{name: "slippage", expected: tt.slippage, received: s.SlippageBps},
+				{name: "twap slippage", expected: tt.twap, received: s.TwapSlippageBps},
+			} {
+				if !v.received.Equal(decimal.RequireFromString(v.expected)) {
+					t.Errorf("test: %s - expected %s: %s - received: %s", tt.description, v.name, v.expected, v.received)
+				}
+			}
+
+			if s.AverageTimeToComplete != tt.average {
+				t.Errorf("test: %s - expected seconds: %f - received: %f", tt.description, tt.average, s.AverageTimeToComplete)
+			}
+		})
+	}
+}
+
+func tcaRecord(submitted time.Time, symbol, orderId, orderType, size, filled, value, price string) *audit.Record {
+	rec := audit.NewOrder(orderId, "", symbol, symbol+"-USD", orderType)
+	rec.Side = "SELL"
+	rec.Status = "filled"
+	rec.SubmittedAt = &submitted
+	rec.OrderSize = size
+	rec.FilledQuantity = filled
+	rec.FilledValue = value
+	rec.Price = price
+	return rec
+}